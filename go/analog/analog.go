@@ -0,0 +1,99 @@
+package analog
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// Channels is the number of single-ended inputs on an MCP3008.
+const Channels = 8
+
+// maxCount is the full-scale reading of the MCP3008's 10-bit converter.
+const maxCount = 1023
+
+// Conn is a full-duplex SPI connection to the ADC.
+type Conn interface {
+	// Tx writes w while reading the same number of bytes into r.
+	Tx(w, r []byte) error
+}
+
+type MCP3008 struct {
+	mu   sync.Mutex
+	conn Conn
+}
+
+func NewMCP3008(conn Conn) *MCP3008 {
+	return &MCP3008{conn: conn}
+}
+
+// ReadChannel returns the raw 0-1023 count for a single-ended channel.
+func (a *MCP3008) ReadChannel(channel int) (int, error) {
+	if channel < 0 || channel >= Channels {
+		return 0, fmt.Errorf("mcp3008 channel %d out of range 0-%d", channel, Channels-1)
+	}
+	// Start bit, then single-ended mode and the channel number in the top
+	// nibble of the second byte. The result comes back in the last 10 bits.
+	w := []byte{0x01, byte(0x08|channel) << 4, 0x00}
+	r := make([]byte, len(w))
+	a.mu.Lock()
+	err := a.conn.Tx(w, r)
+	a.mu.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("mcp3008 channel %d: %w", channel, err)
+	}
+	return int(r[1]&0x03)<<8 | int(r[2]), nil
+}
+
+// DefaultCalibration reports the reading as a percentage of full scale.
+var DefaultCalibration = config.LinearCalibration{RawLow: 0, ValueLow: 0, RawHigh: maxCount, ValueHigh: 100}
+
+// Sensor is an analog probe on one ADC channel.
+type Sensor struct {
+	sync.RWMutex
+	adc         *MCP3008
+	channel     int
+	calibration config.LinearCalibration
+	Name        string  `json:"name"`
+	Location    string  `json:"location"`
+	Unit        string  `json:"unit"`
+	Raw         int     `json:"raw"`
+	Value       float64 `json:"value"`
+}
+
+func NewSensor(adc *MCP3008, channel int, calibration config.LinearCalibration, name, location, unit string) (*Sensor, error) {
+	if err := calibration.Validate(); err != nil {
+		return nil, err
+	}
+	return &Sensor{
+		adc:         adc,
+		channel:     channel,
+		calibration: calibration,
+		Name:        name,
+		Location:    location,
+		Unit:        unit,
+	}, nil
+}
+
+// NewSensorFromConfig builds the sensor for c on adc, falling back to
+// DefaultCalibration when c has none.
+func NewSensorFromConfig(adc *MCP3008, c *config.AnalogSensor) (*Sensor, error) {
+	calibration := DefaultCalibration
+	if c.Calibration != nil {
+		calibration = *c.Calibration
+	}
+	return NewSensor(adc, c.Channel, calibration, c.Name, c.Location, c.Unit)
+}
+
+func (s *Sensor) Read() error {
+	raw, err := s.adc.ReadChannel(s.channel)
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.Raw = raw
+	s.Value = s.calibration.Apply(float64(raw))
+	return nil
+}
@@ -0,0 +1,121 @@
+package analog
+
+import (
+	"math"
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// mockConn emulates an MCP3008 with fixed counts per channel.
+type mockConn struct {
+	counts [Channels]int
+}
+
+func (m *mockConn) Tx(w, r []byte) error {
+	channel := int(w[1]>>4) & 0x07
+	count := m.counts[channel]
+	r[0] = 0
+	r[1] = byte(count>>8) & 0x03
+	r[2] = byte(count)
+	return nil
+}
+
+func TestReadChannel(t *testing.T) {
+	conn := &mockConn{counts: [Channels]int{0, 1, 512, 0, 0, 0, 0, 1023}}
+	adc := NewMCP3008(conn)
+	for channel, want := range conn.counts {
+		got, err := adc.ReadChannel(channel)
+		if err != nil {
+			t.Fatalf("channel %d: %v", channel, err)
+		}
+		if got != want {
+			t.Errorf("channel %d = %d, want %d", channel, got, want)
+		}
+	}
+	if _, err := adc.ReadChannel(Channels); err == nil {
+		t.Error("expected an error for an out of range channel")
+	}
+}
+
+func TestReadChannelCommand(t *testing.T) {
+	var sent []byte
+	adc := NewMCP3008(connFunc(func(w, r []byte) error {
+		sent = append([]byte(nil), w...)
+		return nil
+	}))
+	adc.ReadChannel(5)
+	want := []byte{0x01, 0xD0, 0x00}
+	if string(sent) != string(want) {
+		t.Errorf("sent %x, want %x", sent, want)
+	}
+}
+
+type connFunc func(w, r []byte) error
+
+func (f connFunc) Tx(w, r []byte) error { return f(w, r) }
+
+func TestCalibrationConvert(t *testing.T) {
+	// A capacitive soil probe reading 850 in dry air and 420 in water.
+	soil := config.LinearCalibration{RawLow: 850, ValueLow: 0, RawHigh: 420, ValueHigh: 100}
+	tests := []struct {
+		cal  config.LinearCalibration
+		raw  int
+		want float64
+	}{
+		{DefaultCalibration, 0, 0},
+		{DefaultCalibration, 1023, 100},
+		{soil, 850, 0},
+		{soil, 420, 100},
+		{soil, 635, 50},
+	}
+	for _, tt := range tests {
+		if got := tt.cal.Apply(float64(tt.raw)); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%+v.Apply(%d) = %v, want %v", tt.cal, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestSensorRead(t *testing.T) {
+	adc := NewMCP3008(&mockConn{counts: [Channels]int{2: 635}})
+	s, err := NewSensor(adc, 2, config.LinearCalibration{RawLow: 850, ValueLow: 0, RawHigh: 420, ValueHigh: 100}, "pot 1", "tent", "%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Raw != 635 || math.Abs(s.Value-50) > 1e-9 {
+		t.Errorf("got raw %d value %v, want 635 and 50", s.Raw, s.Value)
+	}
+}
+
+func TestNewSensorRejectsFlatCalibration(t *testing.T) {
+	_, err := NewSensor(NewMCP3008(&mockConn{}), 0, config.LinearCalibration{RawLow: 500, RawHigh: 500}, "", "", "")
+	if err == nil {
+		t.Fatal("expected an error for calibration points with the same raw value")
+	}
+}
+
+func TestNewSensorFromConfig(t *testing.T) {
+	adc := NewMCP3008(&mockConn{counts: [Channels]int{3: 635}})
+	for _, tt := range []struct {
+		cfg  *config.AnalogSensor
+		want float64
+	}{
+		{&config.AnalogSensor{Channel: 3, Name: "pot 1", Unit: "%",
+			Calibration: &config.LinearCalibration{RawLow: 850, ValueLow: 0, RawHigh: 420, ValueHigh: 100}}, 50},
+		{&config.AnalogSensor{Channel: 3, Name: "light"}, 635.0 / 1023 * 100},
+	} {
+		s, err := NewSensorFromConfig(adc, tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Read(); err != nil {
+			t.Fatal(err)
+		}
+		if s.Name != tt.cfg.Name || math.Abs(s.Value-tt.want) > 1e-9 {
+			t.Errorf("%s: got %q value %v, want %v", tt.cfg.Name, s.Name, s.Value, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package analog
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// spiIocMessage1 is SPI_IOC_MESSAGE(1): _IOW('k', 0, struct spi_ioc_transfer).
+const spiIocMessage1 = 0x40206B00
+
+// spiTransfer mirrors struct spi_ioc_transfer from linux/spi/spidev.h.
+type spiTransfer struct {
+	txBuf       uint64
+	rxBuf       uint64
+	length      uint32
+	speedHz     uint32
+	delayUsecs  uint16
+	bitsPerWord uint8
+	csChange    uint8
+	txNbits     uint8
+	rxNbits     uint8
+	wordDelay   uint8
+	pad         uint8
+}
+
+// SpidevConn talks to an SPI device through /dev/spidevB.C.
+type SpidevConn struct {
+	f       *os.File
+	speedHz uint32
+}
+
+// OpenSpidev opens chip select cs on SPI bus, typically bus 0 on a Pi. The
+// MCP3008 is good for about 1.35MHz at 3.3V.
+func OpenSpidev(bus, cs int, speedHz uint32) (*SpidevConn, error) {
+	f, err := os.OpenFile(fmt.Sprintf("/dev/spidev%d.%d", bus, cs), os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spi device %d.%d: %w", bus, cs, err)
+	}
+	return &SpidevConn{f: f, speedHz: speedHz}, nil
+}
+
+func (c *SpidevConn) Tx(w, r []byte) error {
+	if len(w) != len(r) {
+		return fmt.Errorf("spi transfer needs equal buffers, got %d and %d bytes", len(w), len(r))
+	}
+	if len(w) == 0 {
+		return nil
+	}
+	tr := spiTransfer{
+		txBuf:       uint64(uintptr(unsafe.Pointer(&w[0]))),
+		rxBuf:       uint64(uintptr(unsafe.Pointer(&r[0]))),
+		length:      uint32(len(w)),
+		speedHz:     c.speedHz,
+		bitsPerWord: 8,
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, c.f.Fd(), spiIocMessage1, uintptr(unsafe.Pointer(&tr)))
+	// The kernel only sees the buffers as addresses in tr, so keep them
+	// alive until the transfer is done.
+	runtime.KeepAlive(w)
+	runtime.KeepAlive(r)
+	if errno != 0 {
+		return fmt.Errorf("spi transfer failed: %w", errno)
+	}
+	return nil
+}
+
+func (c *SpidevConn) Close() error {
+	return c.f.Close()
+}
+
+// defaultSpeedHz is comfortably inside the MCP3008's limit at 3.3V.
+const defaultSpeedHz = 1_000_000
+
+// OpenConfig opens the ADC described by c and builds its sensors.
+func OpenConfig(c *config.Analog) (*SpidevConn, []*Sensor, error) {
+	speedHz := uint32(defaultSpeedHz)
+	if c.SpeedHz > 0 {
+		speedHz = uint32(c.SpeedHz)
+	}
+	conn, err := OpenSpidev(c.Bus, c.ChipSelect, speedHz)
+	if err != nil {
+		return nil, nil, err
+	}
+	adc := NewMCP3008(conn)
+	sensors := make([]*Sensor, 0, len(c.Sensors))
+	for _, sc := range c.Sensors {
+		s, err := NewSensorFromConfig(adc, sc)
+		if err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("analog %q: %w", sc.Name, err)
+		}
+		sensors = append(sensors, s)
+	}
+	return conn, sensors, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+	"github.com/GreediGoblins/tentbox/go/dht22"
+)
+
+// runBench implements `tentbox bench`: it reads every configured sensor a
+// fixed number of times and reports how reliable and how slow the reads
+// were. It does not start the read cycle.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to the config file, or - to read it from stdin")
+	iterations := fs.Int("iterations", 50, "How many times to read each sensor")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		return errNoConfig
+	}
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	manager, _, err := newDHT22Manager(cfg)
+	if err != nil {
+		return err
+	}
+	readings := manager.Readings()
+	if len(readings) == 0 {
+		return errors.New("no DHT22 sensors configured")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	results := make([]benchResult, len(readings))
+	for i := 0; i < *iterations && ctx.Err() == nil; i++ {
+		for j, r := range readings {
+			readStart := time.Now()
+			_, err := manager.ReadNow(r.Name)
+			results[j].add(time.Since(readStart), err)
+		}
+		fmt.Printf("\riteration %d/%d", i+1, *iterations)
+		// Keep the reads of each sensor one conversion apart.
+		select {
+		case <-ctx.Done():
+		case <-time.After(config.Dht22ConversionGap):
+		}
+	}
+	fmt.Println()
+	fmt.Print(renderBench(readings, results))
+	return nil
+}
+
+// benchResult collects the outcome of every read of one sensor.
+type benchResult struct {
+	latencies []time.Duration
+	failures  int
+	checksum  int
+}
+
+func (b *benchResult) add(latency time.Duration, err error) {
+	b.latencies = append(b.latencies, latency)
+	if err == nil {
+		return
+	}
+	b.failures++
+	if msg := strings.ToLower(err.Error()); strings.Contains(msg, "crc") || strings.Contains(msg, "checksum") {
+		b.checksum++
+	}
+}
+
+func (b *benchResult) successRate() float64 {
+	if len(b.latencies) == 0 {
+		return 0
+	}
+	return float64(len(b.latencies)-b.failures) / float64(len(b.latencies))
+}
+
+type latencyStats struct {
+	Min, Avg, P95, Max time.Duration
+}
+
+// summarize computes latency statistics, using the nearest-rank p95.
+func summarize(latencies []time.Duration) latencyStats {
+	if len(latencies) == 0 {
+		return latencyStats{}
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	rank := (len(sorted)*95 + 99) / 100
+	return latencyStats{
+		Min: sorted[0],
+		Avg: sum / time.Duration(len(sorted)),
+		P95: sorted[rank-1],
+		Max: sorted[len(sorted)-1],
+	}
+}
+
+func renderBench(readings []dht22.Reading, results []benchResult) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PIN\tNAME\tREADS\tOK\tMIN\tAVG\tP95\tMAX\tCRC")
+	for i, r := range readings {
+		res := results[i]
+		s := summarize(res.latencies)
+		fmt.Fprintf(w, "%d\t%s\t%d\t%.0f%%\t%v\t%v\t%v\t%v\t%d\n", r.Pin, r.Name, len(res.latencies),
+			res.successRate()*100, s.Min.Round(time.Millisecond), s.Avg.Round(time.Millisecond),
+			s.P95.Round(time.Millisecond), s.Max.Round(time.Millisecond), res.checksum)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	var latencies []time.Duration
+	for i := 20; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	got := summarize(latencies)
+	want := latencyStats{
+		Min: time.Millisecond,
+		Avg: 10500 * time.Microsecond,
+		P95: 19 * time.Millisecond,
+		Max: 20 * time.Millisecond,
+	}
+	if got != want {
+		t.Errorf("summarize() = %+v, want %+v", got, want)
+	}
+	if got := summarize(nil); got != (latencyStats{}) {
+		t.Errorf("summarize(nil) = %+v, want zero", got)
+	}
+}
+
+func TestBenchResult(t *testing.T) {
+	var b benchResult
+	b.add(time.Millisecond, nil)
+	b.add(time.Millisecond, errors.New("CRCs doesn't match"))
+	b.add(time.Millisecond, errors.New("checksum error"))
+	b.add(time.Millisecond, errors.New("read timed out"))
+	if b.failures != 3 || b.checksum != 2 {
+		t.Errorf("failures = %d, checksum = %d; want 3 and 2", b.failures, b.checksum)
+	}
+	if rate := b.successRate(); rate != 0.25 {
+		t.Errorf("success rate = %v, want 0.25", rate)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/analog"
+	"github.com/GreediGoblins/tentbox/go/config"
+	"github.com/GreediGoblins/tentbox/go/sht31"
+)
+
+// busSensor is a sensor on an I2C or SPI bus. Unlike a DHT22 it reads in
+// milliseconds, so it needs no timeouts or rate limiting.
+type busSensor struct {
+	kind     string
+	name     string
+	location string
+	read     func() error
+	value    func() string
+
+	mu      sync.Mutex
+	readOK  bool
+	lastErr error
+}
+
+// busReading is a point-in-time copy of a busSensor's state. Value is empty
+// until the sensor has read successfully.
+type busReading struct {
+	Kind     string
+	Name     string
+	Location string
+	Value    string
+	Err      error
+}
+
+// busSensors reads the configured SHT31s and analog probes on the same
+// interval as the DHT22 read cycle.
+type busSensors struct {
+	closers []io.Closer
+	sensors []*busSensor
+	wg      sync.WaitGroup
+}
+
+// openBusSensors opens the buses of every SHT31 and the analog ADC in cfg.
+func openBusSensors(cfg *config.Config) (*busSensors, error) {
+	b := &busSensors{}
+	if len(cfg.SHT31) > 0 {
+		buses, sensors, err := sht31.OpenConfig(cfg.SHT31)
+		if err != nil {
+			return nil, err
+		}
+		for _, bus := range buses {
+			b.closers = append(b.closers, bus)
+		}
+		for _, s := range sensors {
+			b.sensors = append(b.sensors, &busSensor{
+				kind:     "sht31",
+				name:     s.Name,
+				location: s.Location,
+				read:     s.Read,
+				value: func() string {
+					s.RLock()
+					defer s.RUnlock()
+					return fmt.Sprintf("%.1f°C %.1f%%", s.Temp, s.Humidity)
+				},
+			})
+		}
+	}
+	if a := cfg.Analog; a != nil && len(a.Sensors) > 0 {
+		conn, sensors, err := analog.OpenConfig(a)
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+		b.closers = append(b.closers, conn)
+		for _, s := range sensors {
+			b.sensors = append(b.sensors, &busSensor{
+				kind:     "analog",
+				name:     s.Name,
+				location: s.Location,
+				read:     s.Read,
+				value: func() string {
+					s.RLock()
+					defer s.RUnlock()
+					return strings.TrimSpace(fmt.Sprintf("%.1f %s", s.Value, s.Unit))
+				},
+			})
+		}
+	}
+	return b, nil
+}
+
+func (b *busSensors) readAll() {
+	for _, s := range b.sensors {
+		err := s.read()
+		if err != nil {
+			fmt.Printf("Failed to read %s %q: %v\n", s.kind, s.name, err)
+		}
+		s.mu.Lock()
+		s.readOK = s.readOK || err == nil
+		s.lastErr = err
+		s.mu.Unlock()
+	}
+}
+
+// run reads every sensor straight away and then every interval until ctx is
+// done. It returns at once when there are no sensors.
+func (b *busSensors) run(ctx context.Context, interval time.Duration) {
+	if len(b.sensors) == 0 {
+		return
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			b.readAll()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (b *busSensors) readings() []busReading {
+	readings := make([]busReading, 0, len(b.sensors))
+	for _, s := range b.sensors {
+		s.mu.Lock()
+		r := busReading{Kind: s.kind, Name: s.name, Location: s.location, Err: s.lastErr}
+		readOK := s.readOK
+		s.mu.Unlock()
+		if readOK {
+			r.Value = s.value()
+		}
+		readings = append(readings, r)
+	}
+	return readings
+}
+
+// Close waits for run to stop and closes the buses. Call it after ctx is
+// done.
+func (b *busSensors) Close() {
+	b.wg.Wait()
+	for _, c := range b.closers {
+		c.Close()
+	}
+}
+
+func renderBusReadings(readings []busReading) string {
+	if len(readings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tNAME\tLOCATION\tVALUE\tSTATUS")
+	for _, r := range readings {
+		value, status := r.Value, "ok"
+		switch {
+		case r.Err != nil:
+			status = "error: " + r.Err.Error()
+		case value == "":
+			status = "waiting"
+		}
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Kind, r.Name, r.Location, value, status)
+	}
+	w.Flush()
+	return b.String()
+}
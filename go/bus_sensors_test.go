@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestOpenBusSensorsEmpty(t *testing.T) {
+	b, err := openBusSensors(&config.Config{})
+	if err != nil {
+		t.Fatalf("openBusSensors with no bus sensors = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b.run(ctx, time.Millisecond)
+	cancel()
+	b.Close()
+	if got := renderBusReadings(b.readings()); got != "" {
+		t.Errorf("renderBusReadings() = %q with no bus sensors", got)
+	}
+}
+
+func TestBusSensors(t *testing.T) {
+	var fail bool
+	b := &busSensors{sensors: []*busSensor{
+		{kind: "sht31", name: "canopy", location: "tent",
+			read:  func() error { return nil },
+			value: func() string { return "24.3°C 55.0%" }},
+		{kind: "sht31", name: "floor", location: "tent",
+			read: func() error {
+				if fail {
+					return errors.New("no device at address")
+				}
+				return nil
+			},
+			value: func() string { return "21.0°C 61.5%" }},
+		{kind: "sht31", name: "ambient", location: "room",
+			read:  func() error { return errors.New("no device at address") },
+			value: func() string { return "0.0°C 0.0%" }},
+		{kind: "analog", name: "soil", location: "pot 1",
+			read:  func() error { return nil },
+			value: func() string { return "42.0 %" }},
+	}}
+
+	want := "\n" +
+		"TYPE    NAME     LOCATION  VALUE  STATUS\n" +
+		"sht31   canopy   tent      -      waiting\n" +
+		"sht31   floor    tent      -      waiting\n" +
+		"sht31   ambient  room      -      waiting\n" +
+		"analog  soil     pot 1     -      waiting\n"
+	if got := renderBusReadings(b.readings()); got != want {
+		t.Errorf("before a read renderBusReadings() =\n%s\nwant\n%s", got, want)
+	}
+
+	b.readAll()
+	fail = true
+	b.readAll()
+	want = "\n" +
+		"TYPE    NAME     LOCATION  VALUE         STATUS\n" +
+		"sht31   canopy   tent      24.3°C 55.0%  ok\n" +
+		"sht31   floor    tent      21.0°C 61.5%  error: no device at address\n" +
+		"sht31   ambient  room      -             error: no device at address\n" +
+		"analog  soil     pot 1     42.0 %        ok\n"
+	if got := renderBusReadings(b.readings()); got != want {
+		t.Errorf("renderBusReadings() =\n%s\nwant\n%s", got, want)
+	}
+}
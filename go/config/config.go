@@ -1,10 +1,100 @@
 package config
 
+import (
+	"errors"
+	"time"
+)
+
 type Config struct {
-	WebServer *WebServer     `json:"webserver"`
-	Dht22     []*Dht22Config `json:"dht22"`
-	DS18B20   []*DS18B20     `json:"ds18b20"`
-	Relay     []*Relay       `json:"relay"`
+	WebServer *WebServer `json:"webserver"`
+	ReadCycle *ReadCycle `json:"read_cycle,omitempty"`
+	// DhtBackend selects the library used to talk to DHT22 sensors,
+	// DhtBackendD2r2 unless set.
+	DhtBackend string         `json:"dht_backend,omitempty" schema:"enum=d2r2|morus12"`
+	Dht22      []*Dht22Config `json:"dht22"`
+	DS18B20    []*DS18B20     `json:"ds18b20"`
+	Relay      []*Relay       `json:"relay"`
+	Analog     *Analog        `json:"analog,omitempty"`
+	SHT31      []*SHT31       `json:"sht31,omitempty"`
+	Zones      []*Zone        `json:"zones,omitempty"`
+}
+
+// Analog describes probes read through an MCP3008 ADC on /dev/spidevB.C.
+// SpeedHz defaults to 1MHz.
+type Analog struct {
+	Bus        int             `json:"bus"`
+	ChipSelect int             `json:"chip_select"`
+	SpeedHz    int             `json:"speed_hz,omitempty"`
+	Sensors    []*AnalogSensor `json:"sensors"`
+}
+
+// AnalogSensor is a probe on one ADC channel. Without a calibration the raw
+// count is reported as a percentage of full scale.
+type AnalogSensor struct {
+	Channel     int                `json:"channel" schema:"required"`
+	Name        string             `json:"name" schema:"required"`
+	Location    string             `json:"location"`
+	Unit        string             `json:"unit,omitempty"`
+	Calibration *LinearCalibration `json:"calibration,omitempty"`
+}
+
+// SHT31 is a temperature and humidity sensor on an I2C bus. Bus defaults to
+// DefaultI2CBus and Address to 0x44, the address with the ADDR pin pulled
+// low.
+type SHT31 struct {
+	Bus      *int   `json:"bus,omitempty"`
+	Address  int    `json:"address,omitempty"`
+	Name     string `json:"name" schema:"required"`
+	Location string `json:"location"`
+}
+
+// DefaultI2CBus is the I2C bus on the Pi's header pins. Bus 0 is reserved
+// for the HAT ID EEPROM.
+const DefaultI2CBus = 1
+
+// BusNumber returns the I2C bus the sensor is on.
+func (s *SHT31) BusNumber() int {
+	if s.Bus == nil {
+		return DefaultI2CBus
+	}
+	return *s.Bus
+}
+
+const (
+	DhtBackendD2r2    = "d2r2"
+	DhtBackendMorus12 = "morus12"
+)
+
+// Dht22ConversionGap is the minimum time between two reads of one DHT22; a
+// read sooner than that returns the previous conversion or fails.
+const Dht22ConversionGap = 2 * time.Second
+
+// DefaultZone is the name given to the implicit zone holding the flat
+// top-level sensor and relay lists.
+const DefaultZone = "default"
+
+// Zone groups the hardware belonging to one tent.
+type Zone struct {
+	Name    string         `json:"name" schema:"required"`
+	Dht22   []*Dht22Config `json:"dht22"`
+	DS18B20 []*DS18B20     `json:"ds18b20"`
+	Relay   []*Relay       `json:"relay"`
+}
+
+// EffectiveZones returns every zone in the config. Anything listed at the top
+// level is returned as an implicit DefaultZone ahead of the named zones, so a
+// single-tent config without a zones section is treated as one zone.
+func (c *Config) EffectiveZones() []*Zone {
+	zones := make([]*Zone, 0, len(c.Zones)+1)
+	if len(c.Dht22) > 0 || len(c.DS18B20) > 0 || len(c.Relay) > 0 {
+		zones = append(zones, &Zone{
+			Name:    DefaultZone,
+			Dht22:   c.Dht22,
+			DS18B20: c.DS18B20,
+			Relay:   c.Relay,
+		})
+	}
+	return append(zones, c.Zones...)
 }
 
 type WebServer struct {
@@ -12,20 +102,151 @@ type WebServer struct {
 	HttpAddress string `json:"http_address"`
 }
 
+// ReadCycle controls how often sensors are polled. ReadTimeout bounds a
+// single sensor read and CycleDeadline bounds a whole pass over all sensors;
+// sensors not reached before the deadline are skipped until the next tick.
+// Zero disables either limit. Sensors are read once at startup unless
+// SkipInitialRead is set, in which case the first read waits for a tick.
+// TimestampSource picks whether readings are stamped when the read started
+// or completed, defaulting to completed.
+type ReadCycle struct {
+	Interval        Duration `json:"interval"`
+	ReadTimeout     Duration `json:"read_timeout"`
+	CycleDeadline   Duration `json:"cycle_deadline"`
+	SkipInitialRead bool     `json:"skip_initial_read"`
+	TimestampSource string   `json:"timestamp_source,omitempty" schema:"enum=read_start|read_complete"`
+	// Retry replaces the DHT22 driver's fixed three retries when set.
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// Health warns when a sensor's recent reads start failing.
+	Health *ReadHealth `json:"health,omitempty"`
+}
+
+// ReadHealth flags a sensor as degraded when fewer than MinSuccessRate of its
+// last Window reads succeeded. A flaky bus usually shows up this way well
+// before reads fail outright.
+type ReadHealth struct {
+	Window         int     `json:"window"`
+	MinSuccessRate float64 `json:"min_success_rate"`
+}
+
+// RetryPolicy bounds the retries within a single sensor read. Delays double
+// from BaseDelay, each with up to Jitter of random spread added, and no
+// retry is started that would run past Budget. Zero disables the budget.
+type RetryPolicy struct {
+	MaxAttempts int      `json:"max_attempts"`
+	BaseDelay   Duration `json:"base_delay"`
+	Jitter      Duration `json:"jitter"`
+	Budget      Duration `json:"budget"`
+}
+
+const (
+	TimestampReadStart    = "read_start"
+	TimestampReadComplete = "read_complete"
+)
+
 type Dht22Config struct {
-	Pin      int    `json:"pin"`
-	Name     string `json:"name"`
-	Location string `json:"location"`
+	Pin      int          `json:"pin" schema:"required"`
+	Name     string       `json:"name" schema:"required"`
+	Location string       `json:"location"`
+	HeightCm int          `json:"height_cm,omitempty"`
+	Position string       `json:"position,omitempty"`
+	Filter   *Dht22Filter `json:"filter,omitempty"`
+	// Samples is how many consecutive reads are taken per reading, using
+	// the median. Defaults to 1.
+	Samples int `json:"samples,omitempty"`
+	// WarmUp is how many successful readings to discard after startup,
+	// since the first reads after power-up can be garbage.
+	WarmUp int `json:"warm_up,omitempty"`
+	// Decimals is how many decimal places readings are rounded to in JSON
+	// output. Defaults to 1, the DHT22's resolution.
+	Decimals *int `json:"decimals,omitempty"`
+	// Gap decides what the sensor reports while its reads are failing.
+	// Defaults to carrying the last value forward indefinitely.
+	Gap *GapPolicy `json:"gap,omitempty"`
+	// Calibration corrects the sensor against a reference instrument.
+	Calibration *Dht22Calibration `json:"calibration,omitempty"`
+}
+
+// Dht22Calibration holds an optional correction per metric.
+type Dht22Calibration struct {
+	Temp     *LinearCalibration `json:"temp,omitempty"`
+	Humidity *LinearCalibration `json:"humidity,omitempty"`
+}
+
+// LinearCalibration maps raw readings onto a reference with a straight line
+// through two (raw, actual) points, e.g. a sensor against a reference
+// instrument or a soil probe in dry air and in water.
+type LinearCalibration struct {
+	RawLow    float64 `json:"raw_low"`
+	ValueLow  float64 `json:"value_low"`
+	RawHigh   float64 `json:"raw_high"`
+	ValueHigh float64 `json:"value_high"`
+}
+
+// Apply returns the calibrated value for raw. A nil calibration returns raw
+// unchanged.
+func (c *LinearCalibration) Apply(raw float64) float64 {
+	if c == nil {
+		return raw
+	}
+	slope := (c.ValueHigh - c.ValueLow) / (c.RawHigh - c.RawLow)
+	return c.ValueLow + slope*(raw-c.RawLow)
+}
+
+// Validate checks that the two points define a line.
+func (c *LinearCalibration) Validate() error {
+	if c != nil && c.RawLow == c.RawHigh {
+		return errors.New("calibration points must have different raw values")
+	}
+	return nil
+}
+
+const (
+	GapCarryForward = "carry_forward"
+	GapLeave        = "gap"
+)
+
+// GapPolicy controls a sensor's values after failed reads. With
+// GapCarryForward the last good values are kept, flagged stale, for up to
+// MaxAge (zero means no limit); after that, or straight away with GapLeave,
+// the sensor reports no values until it reads again.
+type GapPolicy struct {
+	Mode   string   `json:"mode,omitempty" schema:"enum=carry_forward|gap"`
+	MaxAge Duration `json:"max_age"`
+}
+
+// Dht22Filter rejects readings that are physically implausible or that jump
+// too far from the recent average. A zero delta disables that check, and the
+// range check only applies when the max is above the min.
+type Dht22Filter struct {
+	MinTemp          float64 `json:"min_temp"`
+	MaxTemp          float64 `json:"max_temp"`
+	MinHumidity      float64 `json:"min_humidity"`
+	MaxHumidity      float64 `json:"max_humidity"`
+	MaxTempDelta     float64 `json:"max_temp_delta"`
+	MaxHumidityDelta float64 `json:"max_humidity_delta"`
+	Window           int     `json:"window"`
 }
 
 type DS18B20 struct {
-	Id       string `json:"id"`
+	Id       string `json:"id" schema:"required"`
 	Name     string `json:"name"`
 	Location string `json:"location"`
+	HeightCm int    `json:"height_cm,omitempty"`
+	Position string `json:"position,omitempty"`
 }
 
+// Relay is a GPIO-switched output. BootState is the state it is driven to at
+// startup and FailsafeState the state it falls back to when the readings it
+// depends on fail. Pin is optional because configs written before relays had
+// pins leave it out; a relay without one claims no GPIO.
 type Relay struct {
-	Name     string `json:"name"`
-	Location string `json:"location"`
-	Default  bool   `json:"default"`
+	Pin           *int   `json:"pin,omitempty"`
+	Name          string `json:"name" schema:"required"`
+	Location      string `json:"location"`
+	BootState     bool   `json:"boot_state"`
+	FailsafeState bool   `json:"failsafe_state"`
+	// Default is the old name for both states. It is only read, and an
+	// explicit boot_state or failsafe_state overrides it.
+	Default *bool `json:"default,omitempty"`
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 var example_config = &Config{
@@ -10,11 +11,27 @@ var example_config = &Config{
 		HttpPort:    8080,
 		HttpAddress: "0.0.0.0",
 	},
+	ReadCycle: &ReadCycle{
+		Interval:      Duration(30 * time.Second),
+		ReadTimeout:   Duration(5 * time.Second),
+		CycleDeadline: Duration(20 * time.Second),
+	},
 	Dht22: []*Dht22Config{
 		{
 			Pin:      4,
 			Name:     "Living Room",
 			Location: "Home",
+			HeightCm: 120,
+			Position: "canopy",
+			Filter: &Dht22Filter{
+				MinTemp:          -40,
+				MaxTemp:          80,
+				MinHumidity:      0,
+				MaxHumidity:      100,
+				MaxTempDelta:     5,
+				MaxHumidityDelta: 15,
+				Window:           5,
+			},
 		},
 	},
 	DS18B20: []*DS18B20{
@@ -26,13 +43,18 @@ var example_config = &Config{
 	},
 	Relay: []*Relay{
 		{
-			Name:     "Light",
-			Location: "Living Room",
-			Default:  true,
+			Pin:       gpio(17),
+			Name:      "Light",
+			Location:  "Living Room",
+			BootState: true,
 		},
 	},
 }
 
+func gpio(pin int) *int {
+	return &pin
+}
+
 func ExampleConfig() string {
 	out, err := json.MarshalIndent(example_config, "", "  ")
 	if err != nil {
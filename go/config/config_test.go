@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEffectiveZonesFlat(t *testing.T) {
+	var c Config
+	if err := json.Unmarshal([]byte(ExampleConfig()), &c); err != nil {
+		t.Fatalf("failed to unmarshal example config: %v", err)
+	}
+	zones := c.EffectiveZones()
+	if len(zones) != 1 {
+		t.Fatalf("got %d zones, want 1", len(zones))
+	}
+	z := zones[0]
+	if z.Name != DefaultZone {
+		t.Errorf("zone name = %q, want %q", z.Name, DefaultZone)
+	}
+	if len(z.Dht22) != 1 || len(z.DS18B20) != 1 || len(z.Relay) != 1 {
+		t.Errorf("implicit zone is missing hardware: %+v", z)
+	}
+}
+
+func TestEffectiveZonesZoned(t *testing.T) {
+	raw := `{
+		"zones": [
+			{"name": "veg", "dht22": [{"pin": 4, "name": "veg canopy"}], "relay": [{"name": "veg heater"}]},
+			{"name": "flower", "dht22": [{"pin": 17, "name": "flower canopy"}]}
+		]
+	}`
+	var c Config
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+	zones := c.EffectiveZones()
+	if len(zones) != 2 {
+		t.Fatalf("got %d zones, want 2", len(zones))
+	}
+	if zones[0].Name != "veg" || zones[1].Name != "flower" {
+		t.Errorf("got zones %q and %q", zones[0].Name, zones[1].Name)
+	}
+	if len(zones[0].Relay) != 1 || len(zones[1].Relay) != 0 {
+		t.Errorf("relays leaked across zones")
+	}
+}
+
+func TestEffectiveZonesEmpty(t *testing.T) {
+	if zones := (&Config{}).EffectiveZones(); len(zones) != 0 {
+		t.Errorf("got %d zones for empty config, want 0", len(zones))
+	}
+}
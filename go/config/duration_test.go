@@ -0,0 +1,30 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDurationJSON(t *testing.T) {
+	var rc ReadCycle
+	if err := json.Unmarshal([]byte(`{"interval": "1m30s", "read_timeout": "500ms"}`), &rc); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if time.Duration(rc.Interval) != 90*time.Second || time.Duration(rc.ReadTimeout) != 500*time.Millisecond {
+		t.Errorf("got %v and %v", time.Duration(rc.Interval), time.Duration(rc.ReadTimeout))
+	}
+
+	data, err := json.Marshal(Duration(2 * time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"2s"` {
+		t.Errorf("marshaled to %s, want \"2s\"", data)
+	}
+
+	var d Duration
+	if err := json.Unmarshal([]byte(`30`), &d); err == nil {
+		t.Error("expected an error for a bare number")
+	}
+}
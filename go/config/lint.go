@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxGPIO is the highest BCM GPIO number broken out on the Pi's 40-pin header.
+const maxGPIO = 27
+
+// Warning is a non-fatal advisory about a config that loads but is likely to
+// misbehave.
+type Warning string
+
+// Lint returns advisories for setups that are valid but risky: pins that are
+// not plain GPIO, sensors whose samples cannot fit in the read timeout,
+// filter ranges that silently disable themselves and read cycles that overrun
+// their interval.
+func (c *Config) Lint() []Warning {
+	var warnings []Warning
+	owners := make(map[int]string)
+	for _, z := range c.EffectiveZones() {
+		for _, d := range z.Dht22 {
+			owners[d.Pin] = fmt.Sprintf("dht22 %q", d.Name)
+		}
+		for _, r := range z.Relay {
+			if r.Pin != nil {
+				owners[*r.Pin] = fmt.Sprintf("relay %q", r.Name)
+			}
+		}
+	}
+	for _, w := range pinWarnings(owners) {
+		warnings = append(warnings, Warning(w))
+	}
+
+	var readTimeout time.Duration
+	if rc := c.ReadCycle; rc != nil {
+		readTimeout = time.Duration(rc.ReadTimeout)
+		if rc.CycleDeadline > 0 && rc.Interval > 0 && rc.CycleDeadline > rc.Interval {
+			warnings = append(warnings, Warning(fmt.Sprintf(
+				"read_cycle.cycle_deadline %v is longer than the %v interval, so slow cycles will drop ticks",
+				time.Duration(rc.CycleDeadline), time.Duration(rc.Interval))))
+		}
+	}
+
+	for _, z := range c.EffectiveZones() {
+		for _, d := range z.Dht22 {
+			if readTimeout > 0 && d.Samples > 1 && time.Duration(d.Samples-1)*Dht22ConversionGap >= readTimeout {
+				warnings = append(warnings, Warning(fmt.Sprintf(
+					"dht22 %q takes %d samples %v apart, which cannot fit in the %v read timeout",
+					d.Name, d.Samples, Dht22ConversionGap, readTimeout)))
+			}
+			if f := d.Filter; f != nil {
+				if f.MaxTemp <= f.MinTemp && (f.MinTemp != 0 || f.MaxTemp != 0) {
+					warnings = append(warnings, Warning(fmt.Sprintf(
+						"dht22 %q filter max_temp is not above min_temp, so the temperature range is not checked", d.Name)))
+				}
+				if f.MaxHumidity <= f.MinHumidity && (f.MinHumidity != 0 || f.MaxHumidity != 0) {
+					warnings = append(warnings, Warning(fmt.Sprintf(
+						"dht22 %q filter max_humidity is not above min_humidity, so the humidity range is not checked", d.Name)))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+func pinWarnings(owners map[int]string) []string {
+	pins := make([]int, 0, len(owners))
+	for pin := range owners {
+		pins = append(pins, pin)
+	}
+	sort.Ints(pins)
+
+	var warnings []string
+	for _, pin := range pins {
+		switch {
+		case pin < 0 || pin > maxGPIO:
+			warnings = append(warnings, fmt.Sprintf("%s uses pin %d, which is not a GPIO on a Raspberry Pi", owners[pin], pin))
+		case pin == 0 || pin == 1:
+			warnings = append(warnings, fmt.Sprintf("%s uses gpio %d, which is reserved for the HAT ID EEPROM", owners[pin], pin))
+		}
+	}
+	return warnings
+}
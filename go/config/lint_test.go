@@ -0,0 +1,62 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLint(t *testing.T) {
+	c := &Config{
+		ReadCycle: &ReadCycle{
+			Interval:      Duration(10 * time.Second),
+			ReadTimeout:   Duration(3 * time.Second),
+			CycleDeadline: Duration(15 * time.Second),
+		},
+		Dht22: []*Dht22Config{
+			{Pin: 4, Name: "canopy", Samples: 3},
+			{Pin: 5, Name: "floor", Filter: &Dht22Filter{MinTemp: 40, MaxTemp: 10}},
+			{Pin: 6, Name: "ok", Samples: 2, Filter: &Dht22Filter{MaxTempDelta: 5}},
+		},
+		Relay: []*Relay{{Pin: gpio(17), Name: "heater"}},
+	}
+
+	warnings := c.Lint()
+	for _, want := range []string{
+		"cycle_deadline 15s is longer than the 10s interval",
+		`dht22 "canopy" takes 3 samples`,
+		`dht22 "floor" filter max_temp is not above min_temp`,
+	} {
+		found := false
+		for _, w := range warnings {
+			found = found || strings.Contains(string(w), want)
+		}
+		if !found {
+			t.Errorf("missing warning %q in %v", want, warnings)
+		}
+	}
+	if len(warnings) != 3 {
+		t.Errorf("got %d warnings, want 3: %v", len(warnings), warnings)
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	c := &Config{Dht22: []*Dht22Config{{Pin: 4, Name: "canopy"}}}
+	if warnings := c.Lint(); len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestPinWarnings(t *testing.T) {
+	warnings := pinWarnings(map[int]string{
+		1:  `relay "a"`,
+		4:  `dht22 "b"`,
+		40: `relay "c"`,
+	})
+	if len(warnings) != 2 {
+		t.Fatalf("got %d warnings, want 2: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "gpio 1") || !strings.Contains(warnings[1], "pin 40") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// StdinPath is the config path that reads the config from standard input.
+const StdinPath = "-"
+
+// stdin is where LoadConfig reads StdinPath from.
+var stdin io.Reader = os.Stdin
+
+// LoadConfig reads and validates the JSON config at path, or from standard
+// input if path is StdinPath.
+func LoadConfig(path string) (*Config, error) {
+	var data []byte
+	var err error
+	if path == StdinPath {
+		path = "from stdin"
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	c := &Config{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// LoadConfigDir merges every *.json file in dir, in lexical order, into one
+// config and validates the result. Scalars set in a later file override
+// earlier ones, sensor and relay lists are appended, and zones with the same
+// name are merged together.
+func LoadConfigDir(dir string) (*Config, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config dir: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.json config files in %s", dir)
+	}
+
+	c := &Config{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := c.merge(data); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config in %s: %w", dir, err)
+	}
+	return c, nil
+}
+
+// merge decodes a config fragment on top of c. Decoding into c directly lets
+// fields present in the fragment override scalars while leaving absent ones
+// alone; the lists are set aside first so they can be appended instead. The
+// analog section is decoded in place, so its sensor list is set aside too.
+func (c *Config) merge(data []byte) error {
+	prev := *c
+	var analogSensors []*AnalogSensor
+	if c.Analog != nil {
+		analogSensors, c.Analog.Sensors = c.Analog.Sensors, nil
+	}
+	c.Dht22, c.DS18B20, c.Relay, c.SHT31, c.Zones = nil, nil, nil, nil, nil
+	if err := json.Unmarshal(data, c); err != nil {
+		*c = prev
+		if c.Analog != nil {
+			c.Analog.Sensors = analogSensors
+		}
+		return err
+	}
+	c.Dht22 = append(prev.Dht22, c.Dht22...)
+	c.DS18B20 = append(prev.DS18B20, c.DS18B20...)
+	c.Relay = append(prev.Relay, c.Relay...)
+	c.SHT31 = append(prev.SHT31, c.SHT31...)
+	if c.Analog != nil {
+		c.Analog.Sensors = append(analogSensors, c.Analog.Sensors...)
+	}
+	c.Zones = mergeZones(prev.Zones, c.Zones)
+	return nil
+}
+
+func mergeZones(zones, more []*Zone) []*Zone {
+	byName := make(map[string]*Zone, len(zones))
+	for _, z := range zones {
+		byName[z.Name] = z
+	}
+	for _, z := range more {
+		existing, ok := byName[z.Name]
+		if !ok {
+			byName[z.Name] = z
+			zones = append(zones, z)
+			continue
+		}
+		existing.Dht22 = append(existing.Dht22, z.Dht22...)
+		existing.DS18B20 = append(existing.DS18B20, z.DS18B20...)
+		existing.Relay = append(existing.Relay, z.Relay...)
+	}
+	return zones
+}
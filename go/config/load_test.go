@@ -0,0 +1,133 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", ExampleConfig())
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(c.Dht22) != 1 || c.Dht22[0].Pin != 4 {
+		t.Errorf("unexpected dht22 config: %+v", c.Dht22)
+	}
+}
+
+func TestLoadConfigStdin(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", ExampleConfig())
+	want, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader(ExampleConfig())
+	got, err := LoadConfig(StdinPath)
+	if err != nil {
+		t.Fatalf("LoadConfig from stdin failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stdin config = %+v, want %+v", got, want)
+	}
+
+	stdin = strings.NewReader(`{"dht22": [{"pin": 4}, {"pin": 4}]}`)
+	if _, err := LoadConfig(StdinPath); err == nil || !strings.Contains(err.Error(), "stdin") {
+		t.Errorf("invalid stdin config error = %v, want it validated and attributed to stdin", err)
+	}
+}
+
+func TestLoadConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "10-base.json", `{
+		"webserver": {"http_port": 8080, "http_address": "0.0.0.0"},
+		"dht22": [{"pin": 4, "name": "canopy"}],
+		"sht31": [{"name": "a"}],
+		"analog": {"sensors": [{"channel": 0, "name": "soil1"}]},
+		"zones": [{"name": "flower", "dht22": [{"pin": 5, "name": "flower canopy"}]}]
+	}`)
+	writeFile(t, dir, "20-relays.json", `{
+		"webserver": {"http_port": 9090},
+		"relay": [{"pin": 17, "name": "light"}],
+		"sht31": [{"name": "b", "address": 69}],
+		"analog": {"chip_select": 1, "sensors": [{"channel": 1, "name": "soil2"}]},
+		"zones": [{"name": "flower", "relay": [{"pin": 27, "name": "flower light"}]}]
+	}`)
+	writeFile(t, dir, "notes.txt", `not a config`)
+
+	c, err := LoadConfigDir(dir)
+	if err != nil {
+		t.Fatalf("LoadConfigDir failed: %v", err)
+	}
+	if c.WebServer.HttpPort != 9090 {
+		t.Errorf("http port = %d, want the later file's 9090", c.WebServer.HttpPort)
+	}
+	if c.WebServer.HttpAddress != "0.0.0.0" {
+		t.Errorf("http address = %q, want it kept from the earlier file", c.WebServer.HttpAddress)
+	}
+	if len(c.Dht22) != 1 || len(c.Relay) != 1 {
+		t.Errorf("got %d dht22 and %d relays, want 1 of each", len(c.Dht22), len(c.Relay))
+	}
+	if len(c.SHT31) != 2 || c.SHT31[0].Name != "a" || c.SHT31[1].Name != "b" {
+		t.Errorf("sht31 fragments not appended: %+v", c.SHT31)
+	}
+	if c.Analog == nil || c.Analog.ChipSelect != 1 || len(c.Analog.Sensors) != 2 ||
+		c.Analog.Sensors[0].Name != "soil1" || c.Analog.Sensors[1].Name != "soil2" {
+		t.Errorf("analog fragments not merged: %+v", c.Analog)
+	}
+	if len(c.Zones) != 1 {
+		t.Fatalf("got %d zones, want 1 merged zone", len(c.Zones))
+	}
+	if len(c.Zones[0].Dht22) != 1 || len(c.Zones[0].Relay) != 1 {
+		t.Errorf("zone fragments not merged: %+v", c.Zones[0])
+	}
+}
+
+func TestLoadConfigDirConflict(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.json", `{"dht22": [{"pin": 4, "name": "canopy"}]}`)
+	writeFile(t, dir, "b.json", `{"relay": [{"pin": 4, "name": "heater"}]}`)
+
+	if _, err := LoadConfigDir(dir); err == nil {
+		t.Fatal("expected a pin conflict between fragments")
+	}
+}
+
+func TestLoadConfigDirEmpty(t *testing.T) {
+	if _, err := LoadConfigDir(t.TempDir()); err == nil {
+		t.Fatal("expected an error for a dir without config files")
+	}
+}
+
+func TestLoadConfigLegacyRelays(t *testing.T) {
+	// Relays had no pin before they were driven from GPIO.
+	path := writeFile(t, t.TempDir(), "config.json", `{
+		"dht22": [{"pin": 4, "name": "canopy"}],
+		"relay": [
+			{"name": "Light", "location": "tent", "default": true},
+			{"name": "Fan", "location": "tent", "default": false}
+		]
+	}`)
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("legacy relay config failed to load: %v", err)
+	}
+	if len(c.Relay) != 2 || c.Relay[0].Pin != nil || !c.Relay[0].BootState {
+		t.Errorf("unexpected relays: %+v %+v", c.Relay[0], c.Relay[1])
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package config
+
+// lockFile is a no-op where flock is unavailable; writes are still atomic.
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
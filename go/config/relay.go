@@ -0,0 +1,28 @@
+package config
+
+import "encoding/json"
+
+// UnmarshalJSON maps the legacy default field onto both relay states.
+func (r *Relay) UnmarshalJSON(data []byte) error {
+	type plain Relay
+	var v struct {
+		plain
+		BootState     *bool `json:"boot_state"`
+		FailsafeState *bool `json:"failsafe_state"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = Relay(v.plain)
+	if r.Default != nil {
+		r.BootState, r.FailsafeState = *r.Default, *r.Default
+		r.Default = nil
+	}
+	if v.BootState != nil {
+		r.BootState = *v.BootState
+	}
+	if v.FailsafeState != nil {
+		r.FailsafeState = *v.FailsafeState
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRelayStates(t *testing.T) {
+	for _, tc := range []struct {
+		json           string
+		boot, failsafe bool
+	}{
+		{`{"pin": 17, "name": "heater"}`, false, false},
+		{`{"pin": 17, "name": "heater", "default": true}`, true, true},
+		{`{"pin": 17, "name": "fan", "boot_state": false, "failsafe_state": true}`, false, true},
+		{`{"pin": 17, "name": "fan", "default": true, "boot_state": false}`, false, true},
+	} {
+		var r Relay
+		if err := json.Unmarshal([]byte(tc.json), &r); err != nil {
+			t.Fatalf("%s: %v", tc.json, err)
+		}
+		if r.BootState != tc.boot || r.FailsafeState != tc.failsafe {
+			t.Errorf("%s: boot/failsafe = %v/%v, want %v/%v", tc.json, r.BootState, r.FailsafeState, tc.boot, tc.failsafe)
+		}
+		if r.Default != nil {
+			t.Errorf("%s: default was kept after mapping", tc.json)
+		}
+	}
+}
+
+func TestRelayDefaultNotWritten(t *testing.T) {
+	out, err := json.Marshal(&Relay{Pin: gpio(17), Name: "heater", BootState: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"pin":17,"name":"heater","location":"","boot_state":true,"failsafe_state":false}`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSaveConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveConfig(path, example_config); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("saved config is not valid JSON: %v", err)
+	}
+	if got.WebServer.HttpPort != example_config.WebServer.HttpPort {
+		t.Errorf("http port = %d, want %d", got.WebServer.HttpPort, example_config.WebServer.HttpPort)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, ".config.json.tmp-*"))
+	if len(matches) != 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+func TestSaveConfigMarshalFailureKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	original := []byte(`{"webserver":{"http_port":9000}}`)
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := &Config{
+		Dht22: []*Dht22Config{{Pin: 4, Filter: &Dht22Filter{MaxTempDelta: math.NaN()}}},
+	}
+	if err := SaveConfig(path, bad); err == nil {
+		t.Fatal("expected marshal error")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(original) {
+		t.Errorf("existing config was modified: %s", data)
+	}
+}
+
+func TestSaveConfigConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	var wg sync.WaitGroup
+	for port := 8000; port < 8010; port++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := &Config{WebServer: &WebServer{HttpPort: port}}
+			if err := SaveConfig(path, c); err != nil {
+				t.Errorf("SaveConfig failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("config corrupted by concurrent writes: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var durationType = reflect.TypeOf(Duration(0))
+
+// durationPattern matches what time.ParseDuration accepts: an optional sign,
+// then 0 or a sequence of decimal numbers with units.
+const durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// ConfigSchema returns a JSON Schema describing Config. It is generated from
+// the struct definitions so it cannot drift from what LoadConfig accepts.
+// Fields tagged `schema:"required"` are listed as required, and
+// `schema:"enum=a|b"` limits a field to the listed values or "", which
+// always selects the default. Options are separated by commas.
+func ConfigSchema() []byte {
+	schema := schemaFor(reflect.TypeOf(Config{}))
+	schema["$schema"] = schemaDraft
+	schema["title"] = "tentbox config"
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal config schema: %w", err))
+	}
+	return out
+}
+
+func schemaFor(t reflect.Type) map[string]any {
+	if t == durationType {
+		return map[string]any{
+			"type":    "string",
+			"pattern": durationPattern,
+		}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(schemaFor(t.Elem()))
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice:
+		return nullable(map[string]any{"type": "array", "items": schemaFor(t.Elem())})
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	panic(fmt.Sprintf("no schema mapping for config type %s", t))
+}
+
+// nullable allows null as well, since encoding/json accepts it for pointers
+// and slices.
+func nullable(schema map[string]any) map[string]any {
+	schema["type"] = []string{schema["type"].(string), "null"}
+	return schema
+}
+
+func structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		prop := schemaFor(f.Type)
+		for _, opt := range strings.Split(f.Tag.Get("schema"), ",") {
+			if opt == "required" {
+				required = append(required, name)
+			} else if values, ok := strings.CutPrefix(opt, "enum="); ok {
+				prop["enum"] = append([]string{""}, strings.Split(values, "|")...)
+			}
+		}
+		properties[name] = prop
+	}
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+// validateSchema checks v against the subset of JSON Schema that
+// ConfigSchema produces.
+func validateSchema(schema map[string]any, v any, path string) error {
+	if !schemaTypeMatches(schema["type"], v) {
+		return fmt.Errorf("%s: %v does not match type %v", path, v, schema["type"])
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required %q", path, name)
+			}
+		}
+		for name, value := range v {
+			sub, ok := properties[name].(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: unknown property %q", path, name)
+			}
+			if err := validateSchema(sub, value, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items := schema["items"].(map[string]any)
+		for i, item := range v {
+			if err := validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, any(v)) {
+			return fmt.Errorf("%s: %q is not one of %v", path, v, enum)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return fmt.Errorf("%s: %q does not match %s", path, v, pattern)
+		}
+	}
+	return nil
+}
+
+func schemaTypeMatches(want any, v any) bool {
+	var types []string
+	switch want := want.(type) {
+	case string:
+		types = []string{want}
+	case []any:
+		for _, t := range want {
+			types = append(types, t.(string))
+		}
+	}
+	var got []string
+	switch v := v.(type) {
+	case nil:
+		got = []string{"null"}
+	case map[string]any:
+		got = []string{"object"}
+	case []any:
+		got = []string{"array"}
+	case string:
+		got = []string{"string"}
+	case bool:
+		got = []string{"boolean"}
+	case float64:
+		got = []string{"number"}
+		if v == float64(int64(v)) {
+			got = append(got, "integer")
+		}
+	}
+	for _, g := range got {
+		if slices.Contains(types, g) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadSchema(t *testing.T) map[string]any {
+	t.Helper()
+	var schema map[string]any
+	if err := json.Unmarshal(ConfigSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	return schema
+}
+
+func TestExampleConfigMatchesSchema(t *testing.T) {
+	var example any
+	if err := json.Unmarshal([]byte(ExampleConfig()), &example); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSchema(loadSchema(t), example, "config"); err != nil {
+		t.Errorf("example config does not validate: %v", err)
+	}
+}
+
+func TestSchemaRejectsBadConfig(t *testing.T) {
+	schema := loadSchema(t)
+	tests := map[string]string{
+		"wrong type":       `{"webserver": {"http_port": "8080"}}`,
+		"missing pin":      `{"dht22": [{"name": "canopy"}]}`,
+		"unknown field":    `{"dht22": [{"pin": 4, "name": "canopy", "colour": "red"}]}`,
+		"invalid duration": `{"read_cycle": {"interval": "soon"}}`,
+		"unknown backend":  `{"dht_backend": "wiringpi"}`,
+		"unknown gap mode": `{"dht22": [{"pin": 4, "name": "canopy", "gap": {"mode": "zero"}}]}`,
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			var v any
+			if err := json.Unmarshal([]byte(raw), &v); err != nil {
+				t.Fatal(err)
+			}
+			if err := validateSchema(schema, v, "config"); err == nil {
+				t.Errorf("%s validated against the schema", raw)
+			}
+		})
+	}
+}
+
+func TestSchemaAcceptsEnumValues(t *testing.T) {
+	schema := loadSchema(t)
+	raw := fmt.Sprintf(`{"dht_backend": %q, "read_cycle": {"timestamp_source": %q},
+		"dht22": [{"pin": 4, "name": "canopy", "gap": {"mode": %q}}]}`,
+		DhtBackendMorus12, TimestampReadComplete, GapLeave)
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSchema(schema, v, "config"); err != nil {
+		t.Errorf("valid enum values rejected: %v", err)
+	}
+}
+
+func TestSchemaAcceptsDefaults(t *testing.T) {
+	schema := loadSchema(t)
+	raw := `{"dht_backend": "", "read_cycle": {"timestamp_source": "", "interval": "0", "read_timeout": "-1.5s"},
+		"dht22": [{"pin": 4, "name": "canopy", "gap": {"mode": "", "max_age": ".5h"}}]}`
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSchema(schema, v, "config"); err != nil {
+		t.Errorf("config the loader accepts was rejected: %v", err)
+	}
+	c := &Config{}
+	if err := json.Unmarshal([]byte(raw), c); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate rejected the defaults: %v", err)
+	}
+}
+
+func TestDurationPatternMatchesParseDuration(t *testing.T) {
+	pattern := regexp.MustCompile(durationPattern)
+	for _, s := range []string{"0", "+0", "-0", "30s", "-1.5h", "+2m", ".5s", "1.s", "1h30m", "10µs", "10μs", "",
+		"5", "soon", "1x", "1.5", "0s0", "--1s", "1 s"} {
+		_, err := time.ParseDuration(s)
+		if got, want := pattern.MatchString(s), err == nil; got != want {
+			t.Errorf("pattern matches %q = %v, ParseDuration accepts it = %v", s, got, want)
+		}
+	}
+}
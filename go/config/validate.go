@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// mcp3008Channels is the number of single-ended inputs on the analog ADC.
+const mcp3008Channels = 8
+
+// spiPins are the GPIOs of each SPI bus on the Pi's header: MISO, MOSI and
+// SCLK, then the chip selects in order.
+var spiPins = map[int]struct{ bus, chipSelects []int }{
+	0: {bus: []int{9, 10, 11}, chipSelects: []int{8, 7}},
+	1: {bus: []int{19, 20, 21}, chipSelects: []int{18, 17, 16}},
+}
+
+// i2cPins are the SDA and SCL GPIOs of each I2C bus on the Pi's header.
+var i2cPins = map[int][]int{0: {0, 1}, 1: {2, 3}}
+
+// sht31Addresses are the I2C addresses an SHT31 can be strapped to.
+var sht31Addresses = map[int]bool{0x44: true, 0x45: true}
+
+// Validate checks the config for mistakes that would make the hardware
+// misbehave, such as two devices claiming the same GPIO pin. Setups that are
+// merely risky, such as pins that are not usable GPIO on a Pi, are left to
+// Lint.
+func (c *Config) Validate() error {
+	var errs []error
+	owners := make(map[int]string)
+	claim := func(pin int, owner string) {
+		if prev, ok := owners[pin]; ok {
+			errs = append(errs, fmt.Errorf("gpio %d is claimed by both %s and %s", pin, prev, owner))
+			return
+		}
+		owners[pin] = owner
+	}
+
+	zoneNames := make(map[string]bool)
+	for _, z := range c.EffectiveZones() {
+		if zoneNames[z.Name] {
+			errs = append(errs, fmt.Errorf("zone %q is defined more than once", z.Name))
+		}
+		zoneNames[z.Name] = true
+		for _, d := range z.Dht22 {
+			claim(d.Pin, fmt.Sprintf("dht22 %q", d.Name))
+		}
+		for _, r := range z.Relay {
+			if r.Pin != nil {
+				claim(*r.Pin, fmt.Sprintf("relay %q", r.Name))
+			}
+		}
+	}
+	i2cClaimed := make(map[int]bool)
+	for _, s := range c.SHT31 {
+		bus := s.BusNumber()
+		if i2cClaimed[bus] {
+			continue
+		}
+		i2cClaimed[bus] = true
+		for _, pin := range i2cPins[bus] {
+			claim(pin, fmt.Sprintf("sht31 i2c-%d", bus))
+		}
+	}
+	if a := c.Analog; a != nil {
+		if pins, ok := spiPins[a.Bus]; ok {
+			owner := fmt.Sprintf("analog spidev%d.%d", a.Bus, a.ChipSelect)
+			for _, pin := range pins.bus {
+				claim(pin, owner)
+			}
+			if a.ChipSelect >= 0 && a.ChipSelect < len(pins.chipSelects) {
+				claim(pins.chipSelects[a.ChipSelect], owner)
+			}
+		}
+	}
+
+	switch c.DhtBackend {
+	case "", DhtBackendD2r2, DhtBackendMorus12:
+	default:
+		errs = append(errs, fmt.Errorf("dht_backend %q must be %q or %q",
+			c.DhtBackend, DhtBackendD2r2, DhtBackendMorus12))
+	}
+	for _, z := range c.EffectiveZones() {
+		for _, d := range z.Dht22 {
+			if d.Gap != nil {
+				switch d.Gap.Mode {
+				case "", GapCarryForward, GapLeave:
+				default:
+					errs = append(errs, fmt.Errorf("dht22 %q gap.mode %q must be %q or %q",
+						d.Name, d.Gap.Mode, GapCarryForward, GapLeave))
+				}
+			}
+			if cal := d.Calibration; cal != nil {
+				if err := cal.Temp.Validate(); err != nil {
+					errs = append(errs, fmt.Errorf("dht22 %q temp: %w", d.Name, err))
+				}
+				if err := cal.Humidity.Validate(); err != nil {
+					errs = append(errs, fmt.Errorf("dht22 %q humidity: %w", d.Name, err))
+				}
+			}
+		}
+	}
+	if a := c.Analog; a != nil {
+		channels := make(map[int]string)
+		for _, s := range a.Sensors {
+			if s.Channel < 0 || s.Channel >= mcp3008Channels {
+				errs = append(errs, fmt.Errorf("analog %q channel %d is out of range 0-%d", s.Name, s.Channel, mcp3008Channels-1))
+			}
+			if prev, ok := channels[s.Channel]; ok {
+				errs = append(errs, fmt.Errorf("analog channel %d is claimed by both %q and %q", s.Channel, prev, s.Name))
+			}
+			channels[s.Channel] = s.Name
+			if err := s.Calibration.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("analog %q: %w", s.Name, err))
+			}
+		}
+	}
+	sht31Devices := make(map[[2]int]string)
+	for _, s := range c.SHT31 {
+		addr := s.Address
+		if addr == 0 {
+			addr = 0x44
+		}
+		if !sht31Addresses[addr] {
+			errs = append(errs, fmt.Errorf("sht31 %q address 0x%02x must be 0x44 or 0x45", s.Name, addr))
+		}
+		key := [2]int{s.BusNumber(), addr}
+		if prev, ok := sht31Devices[key]; ok {
+			errs = append(errs, fmt.Errorf("sht31 address 0x%02x on bus %d is claimed by both %q and %q", addr, s.BusNumber(), prev, s.Name))
+		}
+		sht31Devices[key] = s.Name
+	}
+	if rc := c.ReadCycle; rc != nil {
+		switch rc.TimestampSource {
+		case "", TimestampReadStart, TimestampReadComplete:
+		default:
+			errs = append(errs, fmt.Errorf("read_cycle.timestamp_source %q must be %q or %q",
+				rc.TimestampSource, TimestampReadStart, TimestampReadComplete))
+		}
+		if h := rc.Health; h != nil {
+			if h.Window < 0 {
+				errs = append(errs, fmt.Errorf("read_cycle.health.window %d must not be negative", h.Window))
+			}
+			if h.MinSuccessRate < 0 || h.MinSuccessRate > 1 {
+				errs = append(errs, fmt.Errorf("read_cycle.health.min_success_rate %v must be between 0 and 1", h.MinSuccessRate))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateExample(t *testing.T) {
+	if err := example_config.Validate(); err != nil {
+		t.Errorf("example config failed validation: %v", err)
+	}
+}
+
+func TestValidatePinConflict(t *testing.T) {
+	c := &Config{
+		Dht22: []*Dht22Config{{Pin: 4, Name: "canopy"}},
+		Relay: []*Relay{
+			{Pin: gpio(4), Name: "heater"},
+			{Pin: gpio(17), Name: "fan"},
+		},
+	}
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("expected a pin conflict error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `dht22 "canopy"`) || !strings.Contains(msg, `relay "heater"`) {
+		t.Errorf("error does not name both owners: %v", msg)
+	}
+	if strings.Contains(msg, "fan") {
+		t.Errorf("error mentions a relay without a conflict: %v", msg)
+	}
+}
+
+func TestValidatePinConflictAcrossZones(t *testing.T) {
+	c := &Config{
+		Zones: []*Zone{
+			{Name: "veg", Dht22: []*Dht22Config{{Pin: 22, Name: "veg canopy"}}},
+			{Name: "flower", Relay: []*Relay{{Pin: gpio(22), Name: "flower light"}}},
+		},
+	}
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected a pin conflict error across zones")
+	}
+}
+
+func TestValidateDuplicateZone(t *testing.T) {
+	c := &Config{
+		Zones: []*Zone{{Name: "tent"}, {Name: "tent"}},
+	}
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected a duplicate zone error")
+	}
+}
+
+func TestValidateTimestampSource(t *testing.T) {
+	c := &Config{ReadCycle: &ReadCycle{TimestampSource: TimestampReadStart}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.ReadCycle.TimestampSource = "whenever"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected an unknown timestamp_source error")
+	}
+}
+
+func TestValidateReadHealth(t *testing.T) {
+	c := &Config{ReadCycle: &ReadCycle{Health: &ReadHealth{Window: 10, MinSuccessRate: 0.8}}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, bad := range []ReadHealth{
+		{Window: 10, MinSuccessRate: 80},
+		{Window: 10, MinSuccessRate: -0.1},
+		{Window: -1, MinSuccessRate: 0.8},
+	} {
+		c.ReadCycle.Health = &bad
+		if err := c.Validate(); err == nil {
+			t.Errorf("expected an error for health %+v", bad)
+		}
+	}
+}
+
+func TestValidateDhtBackend(t *testing.T) {
+	c := &Config{DhtBackend: DhtBackendMorus12}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.DhtBackend = "wiringpi"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected an unknown dht_backend error")
+	}
+}
+
+func TestValidateGapMode(t *testing.T) {
+	c := &Config{Dht22: []*Dht22Config{{Pin: 4, Name: "canopy", Gap: &GapPolicy{Mode: GapLeave}}}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Dht22[0].Gap.Mode = "interpolate"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected an unknown gap mode error")
+	}
+}
+
+func TestValidateCalibration(t *testing.T) {
+	c := &Config{Dht22: []*Dht22Config{{Pin: 4, Name: "canopy", Calibration: &Dht22Calibration{
+		Temp: &LinearCalibration{RawLow: 21, ValueLow: 20, RawHigh: 33, ValueHigh: 30},
+	}}}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Dht22[0].Calibration.Humidity = &LinearCalibration{RawLow: 50, ValueLow: 50, RawHigh: 50, ValueHigh: 60}
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected an error for calibration points with the same raw value")
+	}
+}
+
+func TestValidateAnalog(t *testing.T) {
+	c := &Config{Analog: &Analog{Sensors: []*AnalogSensor{
+		{Channel: 0, Name: "pot 1", Calibration: &LinearCalibration{RawLow: 850, RawHigh: 420, ValueHigh: 100}},
+		{Channel: 1, Name: "light"},
+	}}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, bad := range []*AnalogSensor{
+		{Channel: 8, Name: "out of range"},
+		{Channel: 1, Name: "duplicate"},
+		{Channel: 2, Name: "flat", Calibration: &LinearCalibration{RawLow: 500, RawHigh: 500}},
+	} {
+		c := &Config{Analog: &Analog{Sensors: append([]*AnalogSensor{{Channel: 1, Name: "light"}}, bad)}}
+		if err := c.Validate(); err == nil {
+			t.Errorf("expected an error for analog sensor %q", bad.Name)
+		}
+	}
+}
+
+func TestValidateAnalogPins(t *testing.T) {
+	for _, tc := range []struct {
+		analog  Analog
+		pin     int
+		wantErr bool
+	}{
+		{Analog{}, 10, true},
+		{Analog{}, 8, true},
+		{Analog{}, 7, false},
+		{Analog{ChipSelect: 1}, 7, true},
+		{Analog{Bus: 1}, 10, false},
+		{Analog{Bus: 1}, 20, true},
+	} {
+		c := &Config{Analog: &tc.analog, Dht22: []*Dht22Config{{Pin: tc.pin, Name: "canopy"}}}
+		if err := c.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("spidev%d.%d with a dht22 on gpio %d: err = %v, want error %v",
+				tc.analog.Bus, tc.analog.ChipSelect, tc.pin, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateSHT31Pins(t *testing.T) {
+	i2cBus := func(n int) *int { return &n }
+	for _, tc := range []struct {
+		bus     *int
+		pin     int
+		wantErr bool
+	}{
+		{nil, 2, true},
+		{nil, 3, true},
+		{nil, 1, false},
+		{i2cBus(0), 1, true},
+		{i2cBus(0), 3, false},
+	} {
+		c := &Config{
+			SHT31: []*SHT31{{Bus: tc.bus, Name: "canopy"}, {Bus: tc.bus, Address: 0x45, Name: "floor"}},
+			Relay: []*Relay{{Pin: gpio(tc.pin), Name: "light"}},
+		}
+		if err := c.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("i2c-%d with a relay on gpio %d: err = %v, want error %v",
+				c.SHT31[0].BusNumber(), tc.pin, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateSHT31(t *testing.T) {
+	i2cBus := func(n int) *int { return &n }
+	c := &Config{SHT31: []*SHT31{
+		{Name: "canopy"},
+		{Address: 0x45, Name: "floor"},
+		{Bus: i2cBus(0), Name: "outside"},
+	}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, bad := range []*SHT31{
+		{Address: 0x40, Name: "wrong address"},
+		{Address: 0x44, Name: "duplicate"},
+		{Bus: i2cBus(DefaultI2CBus), Name: "duplicate on the default bus"},
+	} {
+		c := &Config{SHT31: []*SHT31{{Name: "canopy"}, bad}}
+		if err := c.Validate(); err == nil {
+			t.Errorf("expected an error for sht31 %q", bad.Name)
+		}
+	}
+}
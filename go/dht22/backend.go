@@ -0,0 +1,77 @@
+package dht22
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	dht "github.com/d2r2/go-dht"
+	morus "github.com/morus12/dht22"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// driver is one DHT22 library, by its config.DhtBackend name. read is a
+// complete read, including any retrying the library does itself; once is a
+// single attempt, for use under a retry policy.
+type driver struct {
+	name string
+	read readFunc
+	once readFunc
+}
+
+// Both libraries bit-bang the same protocol, but on some kernels one of them
+// cannot get at the GPIO and the other can.
+var drivers = map[string]driver{
+	config.DhtBackendD2r2:    {name: config.DhtBackendD2r2, read: guard(readDHT22), once: guard(readDHT22Once)},
+	config.DhtBackendMorus12: {name: config.DhtBackendMorus12, read: guard(readMorus12), once: guard(readMorus12)},
+}
+
+// guard turns a panic in a driver into a permanent ReadError, so a driver bug
+// fails the read instead of taking down the process. morus12, for one,
+// dereferences a nil GPIO driver when embd cannot initialise.
+func guard(fn readFunc) readFunc {
+	return func(ctx context.Context, pin int) (temperature, humidity float32, retried int, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				temperature, humidity, retried = -1, -1, 0
+				err = &ReadError{Pin: pin, Err: fmt.Errorf("driver panicked: %v", r)}
+			}
+		}()
+		return fn(ctx, pin)
+	}
+}
+
+func lookupDriver(name string) (driver, error) {
+	if name == "" {
+		name = config.DhtBackendD2r2
+	}
+	d, ok := drivers[name]
+	if !ok {
+		return driver{}, fmt.Errorf("unknown dht backend %q", name)
+	}
+	return d, nil
+}
+
+func readDHT22Once(_ context.Context, pin int) (float32, float32, int, error) {
+	temperature, humidity, err := dht.ReadDHTxx(dht.DHT22, pin, false)
+	return temperature, humidity, 0, classify(pin, err)
+}
+
+// readMorus12 reads through morus12/dht22, which goes through embd rather
+// than sysfs. embd names Pi pins by their bare BCM number. It caches a
+// conversion for two seconds, so the humidity call returns the same reading
+// as the temperature call. The library has no way to abandon a read, so ctx
+// is ignored.
+func readMorus12(_ context.Context, pin int) (float32, float32, int, error) {
+	sensor := morus.New(strconv.Itoa(pin))
+	temperature, err := sensor.Temperature()
+	if err != nil {
+		return 0, 0, 0, classify(pin, err)
+	}
+	humidity, err := sensor.Humidity()
+	if err != nil {
+		return 0, 0, 0, classify(pin, err)
+	}
+	return temperature, humidity, 0, nil
+}
@@ -0,0 +1,60 @@
+package dht22
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestLookupDriver(t *testing.T) {
+	for _, name := range []string{"", config.DhtBackendD2r2, config.DhtBackendMorus12} {
+		d, err := lookupDriver(name)
+		if err != nil {
+			t.Fatalf("lookupDriver(%q): %v", name, err)
+		}
+		want := name
+		if want == "" {
+			want = config.DhtBackendD2r2
+		}
+		if d.name != want || d.read == nil || d.once == nil {
+			t.Errorf("lookupDriver(%q) = %q, want %q with both readers set", name, d.name, want)
+		}
+	}
+	if _, err := lookupDriver("wiringpi"); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
+
+func TestGuardRecoversDriverPanic(t *testing.T) {
+	read := guard(func(ctx context.Context, pin int) (float32, float32, int, error) {
+		var gpio *struct{ closed bool }
+		gpio.closed = true
+		return 24, 55, 0, nil
+	})
+	_, _, _, err := read(context.Background(), 4)
+	if err == nil {
+		t.Fatal("panicking driver returned no error")
+	}
+	if IsTransient(err) {
+		t.Errorf("err = %v, want a permanent read error", err)
+	}
+}
+
+func TestManagerBackendAppliedOnReconcile(t *testing.T) {
+	m := NewManager()
+	if err := m.SetBackend(config.DhtBackendMorus12); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Name: "canopy"}}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Sensors[4].readFn == nil {
+		t.Fatal("reconciled sensor has no reader")
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.driver.name != config.DhtBackendMorus12 {
+		t.Errorf("manager driver = %q, want %q", m.driver.name, config.DhtBackendMorus12)
+	}
+}
@@ -0,0 +1,40 @@
+package dht22
+
+import (
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestTwoPointCalibration(t *testing.T) {
+	cal := &config.Dht22Calibration{
+		// Reads 1C high at 20C and 3C high at 30C.
+		Temp: &config.LinearCalibration{RawLow: 21, ValueLow: 20, RawHigh: 33, ValueHigh: 30},
+		// Reads 5% low across the range.
+		Humidity: &config.LinearCalibration{RawLow: 40, ValueLow: 45, RawHigh: 70, ValueHigh: 75},
+	}
+	for _, tc := range []struct {
+		raw        reading
+		temp, hum  float64
+		calibrated bool
+	}{
+		{reading{21, 40}, 20, 45, true},
+		{reading{33, 70}, 30, 75, true},
+		{reading{27, 55}, 25, 60, true},
+		{reading{15, 30}, 15, 35, true},
+		{reading{27, 55}, 27, 55, false},
+	} {
+		d := NewDHT22(4, "canopy", "tent")
+		d.readFn = seriesReader([]reading{tc.raw})
+		if tc.calibrated {
+			d.SetCalibration(cal)
+		}
+		if err := d.read(); err != nil {
+			t.Fatal(err)
+		}
+		if d.Temp != tc.temp || d.Humidity != tc.hum {
+			t.Errorf("raw %v/%v (calibrated %v): got %v/%v, want %v/%v",
+				tc.raw.temp, tc.raw.humidity, tc.calibrated, d.Temp, d.Humidity, tc.temp, tc.hum)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package dht22
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func delayedReader(delay time.Duration) readFunc {
+	return func(ctx context.Context, pin int) (float32, float32, int, error) {
+		time.Sleep(delay)
+		return 24, 55, 0, nil
+	}
+}
+
+func newCycleManager(t *testing.T, delays ...time.Duration) (*Manager, []*DHT22) {
+	t.Helper()
+	m := NewManager()
+	var sensors []*DHT22
+	for i, delay := range delays {
+		s := NewDHT22(i+1, "", "tent")
+		s.readFn = delayedReader(delay)
+		if err := m.AddSensor(s); err != nil {
+			t.Fatal(err)
+		}
+		sensors = append(sensors, s)
+	}
+	return m, sensors
+}
+
+func TestCycleDeadlineSkipsRemaining(t *testing.T) {
+	m, sensors := newCycleManager(t, 0, 60*time.Millisecond, 0)
+	m.SetTimeouts(0, 30*time.Millisecond)
+
+	start := time.Now()
+	m.readAll()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("cycle took %v, want it cut off near the 30ms deadline", elapsed)
+	}
+
+	fast, slow, last := sensors[0].ReadStats(), sensors[1].ReadStats(), sensors[2].ReadStats()
+	if fast.Reads != 1 || fast.Failures != 0 {
+		t.Errorf("fast sensor stats = %+v, want one good read", fast)
+	}
+	if slow.Reads != 1 || slow.Failures != 1 {
+		t.Errorf("slow sensor stats = %+v, want one timed out read", slow)
+	}
+	if last.Reads != 0 || last.Skipped != 1 {
+		t.Errorf("last sensor stats = %+v, want it skipped", last)
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	m, sensors := newCycleManager(t, 0, 60*time.Millisecond, 0)
+	m.SetTimeouts(10*time.Millisecond, 0)
+
+	m.readAll()
+
+	slow := sensors[1].ReadStats()
+	if slow.Failures != 1 {
+		t.Errorf("slow sensor stats = %+v, want a timeout failure", slow)
+	}
+	for _, i := range []int{0, 2} {
+		if stats := sensors[i].ReadStats(); stats.Reads != 1 || stats.Failures != 0 {
+			t.Errorf("sensor %d stats = %+v, want one good read", i, stats)
+		}
+	}
+
+	// The abandoned read is still on the bus, so the next cycle skips it.
+	m.readAll()
+	if slow = sensors[1].ReadStats(); slow.Skipped != 1 {
+		t.Errorf("slow sensor stats = %+v, want the overlapping read skipped", slow)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	m.readAll()
+	if slow = sensors[1].ReadStats(); slow.Reads != 2 {
+		t.Errorf("slow sensor stats = %+v, want it read again once free", slow)
+	}
+}
+
+func TestInitialRead(t *testing.T) {
+	m, sensors := newCycleManager(t, 0)
+	m.StartReadCycle(time.Hour)
+	defer m.StopReadCycle()
+
+	deadline := time.Now().Add(time.Second)
+	for sensors[0].ReadStats().Reads == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("sensor was not read before the first tick")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if r := sensors[0].Reading(); r.Temp != 24 || r.Humidity != 55 {
+		t.Errorf("got %v/%v after the initial read, want 24/55", r.Temp, r.Humidity)
+	}
+}
+
+func TestSkipInitialRead(t *testing.T) {
+	m, sensors := newCycleManager(t, 0)
+	m.SetInitialRead(false)
+	m.StartReadCycle(time.Hour)
+	defer m.StopReadCycle()
+
+	time.Sleep(20 * time.Millisecond)
+	if reads := sensors[0].ReadStats().Reads; reads != 0 {
+		t.Errorf("reads = %d before the first tick, want 0", reads)
+	}
+}
+
+func TestTimestampSource(t *testing.T) {
+	for _, tc := range []struct {
+		source  string
+		atStart bool
+	}{
+		{config.TimestampReadStart, true},
+		{config.TimestampReadComplete, false},
+		{"", false},
+	} {
+		m, sensors := newCycleManager(t, 50*time.Millisecond)
+		m.SetTimestampSource(tc.source)
+
+		start := time.Now()
+		m.readAll()
+		end := time.Now()
+
+		ts := sensors[0].Reading().Timestamp
+		if tc.atStart && (ts.Before(start) || ts.Sub(start) > 20*time.Millisecond) {
+			t.Errorf("source %q: timestamp %v after start, want near the read start", tc.source, ts.Sub(start))
+		}
+		if !tc.atStart && (ts.Sub(start) < 50*time.Millisecond || ts.After(end)) {
+			t.Errorf("source %q: timestamp %v after start, want at read completion", tc.source, ts.Sub(start))
+		}
+	}
+}
+
+func TestSetInterval(t *testing.T) {
+	m, sensors := newCycleManager(t, 0)
+	m.SetInterval(time.Millisecond)
+	if s := m.Status(); s.Running || s.Interval != 0 {
+		t.Fatalf("status = %+v after SetInterval on a stopped cycle", s)
+	}
+
+	m.StartReadCycle(time.Hour)
+	defer m.StopReadCycle()
+	m.SetInterval(5 * time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for sensors[0].ReadStats().Reads < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("read cycle did not pick up the shorter interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if s := m.Status(); s.Interval != 5*time.Millisecond {
+		t.Errorf("status interval = %v, want 5ms", s.Interval)
+	}
+}
@@ -1,31 +1,161 @@
 package dht22
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	dht "github.com/d2r2/go-dht"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// readFunc reads a single temperature/humidity pair from the sensor on pin.
+// A driver that retries stops doing so once ctx is done.
+type readFunc func(ctx context.Context, pin int) (temperature float32, humidity float32, retried int, err error)
+
+func readDHT22(ctx context.Context, pin int) (float32, float32, int, error) {
+	temperature, humidity, retried, err := dht.ReadDHTxxWithContextAndRetry(ctx, dht.DHT22, pin, false, 3)
+	return temperature, humidity, retried, classify(pin, err)
+}
+
+var (
+	errReadTimeout    = errors.New("read timed out")
+	errReadInProgress = errors.New("previous read is still in progress")
 )
 
+type readResult struct {
+	temperature float32
+	humidity    float32
+	retried     int
+	start       time.Time
+	elapsed     time.Duration
+	err         error
+}
+
 type DHT22 struct {
 	sync.RWMutex
-	pin      int
-	Name     string  `json:"name"`
-	Location string  `json:"location"`
-	Temp     float64 `json:"temp"`
-	Humidity float64 `json:"humidity"`
+	pin     int
+	readFn  readFunc
+	reading atomic.Bool
+	samples int
+	// sampleGap is the pause between samples; a DHT22 needs about two
+	// seconds between conversions.
+	sampleGap time.Duration
+	filter    *filter
+	warmUp    int
+	decimals  int
+	gap       config.GapPolicy
+	// calibration is never nil; its metrics are nil when uncalibrated.
+	calibration *config.Dht22Calibration
+	enabled     bool
+	Name        string
+	Location    string
+	HeightCm    int
+	Position    string
+	Temp        float64
+	Humidity    float64
+	// Timestamp is when the current values were read.
+	Timestamp time.Time
+	// Quality says whether Temp and Humidity came from the latest read.
+	Quality  Quality
+	Rejected uint64
+	Stats    Stats
+	// LastError describes the most recent failed read and is cleared by the
+	// next successful one.
+	LastError *LastError
+}
+
+type LastError struct {
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+	Transient bool      `json:"transient"`
 }
 
 func NewDHT22(pin int, name string, location string) *DHT22 {
 	return &DHT22{
-		pin:      pin,
-		Name:     name,
-		Location: location,
+		pin:         pin,
+		readFn:      drivers[config.DhtBackendD2r2].read,
+		samples:     1,
+		sampleGap:   config.Dht22ConversionGap,
+		decimals:    defaultDecimals,
+		calibration: &config.Dht22Calibration{},
+		enabled:     true,
+		Name:        name,
+		Location:    location,
+	}
+}
+
+func NewDHT22FromConfig(c *config.Dht22Config) *DHT22 {
+	d := NewDHT22(c.Pin, c.Name, c.Location)
+	d.SetFilter(c.Filter)
+	d.SetSamples(c.Samples)
+	d.SetPlacement(c.HeightCm, c.Position)
+	d.SetWarmUp(c.WarmUp)
+	if c.Decimals != nil {
+		d.SetDecimals(*c.Decimals)
+	}
+	d.SetGapPolicy(c.Gap)
+	d.SetCalibration(c.Calibration)
+	return d
+}
+
+// SetCalibration corrects subsequent readings with two-point linear fits. A
+// nil calibration, or a nil metric within it, leaves readings as the driver
+// reports them.
+func (d *DHT22) SetCalibration(c *config.Dht22Calibration) {
+	d.Lock()
+	defer d.Unlock()
+	d.calibration = &config.Dht22Calibration{}
+	if c != nil {
+		d.calibration = c
+	}
+}
+
+// SetGapPolicy sets what the sensor reports while reads fail. A nil policy
+// carries the last values forward indefinitely.
+func (d *DHT22) SetGapPolicy(p *config.GapPolicy) {
+	d.Lock()
+	defer d.Unlock()
+	d.gap = config.GapPolicy{}
+	if p != nil {
+		d.gap = *p
 	}
 }
 
+// SetDecimals sets how many decimal places the JSON output rounds readings
+// to. The stored values keep full precision.
+func (d *DHT22) SetDecimals(n int) {
+	d.Lock()
+	defer d.Unlock()
+	d.decimals = max(n, 0)
+}
+
+// SetWarmUp discards the next n successful readings, so power-up garbage
+// never reaches the stored values or the filter history. The reads still
+// count in the stats.
+func (d *DHT22) SetWarmUp(n int) {
+	d.Lock()
+	defer d.Unlock()
+	d.warmUp = max(n, 0)
+}
+
+// SetSamples makes each reading the median of n consecutive samples, which
+// rejects single-sample glitches. Every extra sample costs a driver call plus
+// sampleGap of bus time, so read timeouts and the cycle deadline need to
+// allow for it. Values below 1 mean a single sample.
+func (d *DHT22) SetSamples(n int) {
+	d.Lock()
+	defer d.Unlock()
+	d.samples = max(n, 1)
+}
+
 func (d *DHT22) SetName(name string) {
 	d.Lock()
 	defer d.Unlock()
@@ -38,55 +168,584 @@ func (d *DHT22) SetLocation(location string) {
 	d.Location = location
 }
 
-func (d *DHT22) read() {
-	temperature, humidity, retried, err := dht.ReadDHTxxWithRetry(dht.DHT22, d.pin, false, 3)
-	if err != nil {
-		fmt.Printf("Failed to get a successful reading after %d attempts\n", retried)
+// SetPlacement records where the sensor sits in the tent, so readings at
+// different heights can be compared.
+func (d *DHT22) SetPlacement(heightCm int, position string) {
+	d.Lock()
+	defer d.Unlock()
+	d.HeightCm = heightCm
+	d.Position = position
+}
+
+// Reading is a point-in-time copy of a sensor's state.
+type Reading struct {
+	Pin       int
+	Name      string
+	Location  string
+	HeightCm  int
+	Position  string
+	Temp      float64
+	Humidity  float64
+	Timestamp time.Time
+	Quality   Quality
+	Rejected  uint64
+	Stats     Stats
+	Enabled   bool
+	LastError *LastError
+	// Decimals is how many decimal places the values are meaningful to.
+	Decimals int
+}
+
+func (d *DHT22) Reading() Reading {
+	d.RLock()
+	defer d.RUnlock()
+	return Reading{
+		Pin:       d.pin,
+		Name:      d.Name,
+		Location:  d.Location,
+		HeightCm:  d.HeightCm,
+		Position:  d.Position,
+		Temp:      d.Temp,
+		Humidity:  d.Humidity,
+		Timestamp: d.Timestamp,
+		Quality:   d.Quality,
+		Rejected:  d.Rejected,
+		Stats:     d.Stats,
+		Enabled:   d.enabled,
+		LastError: d.LastError,
+		Decimals:  d.decimals,
+	}
+}
+
+// SetEnabled controls whether the read cycle polls the sensor. A disabled
+// sensor keeps its last values but is not read, so it neither fails nor
+// goes stale while unplugged.
+func (d *DHT22) SetEnabled(enabled bool) {
+	d.Lock()
+	defer d.Unlock()
+	d.enabled = enabled
+}
+
+func (d *DHT22) Enabled() bool {
+	d.RLock()
+	defer d.RUnlock()
+	return d.enabled
+}
+
+// SetFilter enables outlier rejection for the sensor. A nil filter disables it.
+func (d *DHT22) SetFilter(f *config.Dht22Filter) {
+	d.Lock()
+	defer d.Unlock()
+	if f == nil {
+		d.filter = nil
 		return
 	}
-	d.Temp = float64(temperature)
-	d.Humidity = float64(humidity)
+	d.filter = newFilter(f)
+}
+
+// ReadStats returns a copy of the sensor's read statistics.
+func (d *DHT22) ReadStats() Stats {
+	d.RLock()
+	defer d.RUnlock()
+	return d.Stats
+}
+
+func (d *DHT22) read() error {
+	return d.readWithTimeout(0, false)
+}
+
+// readWithTimeout reads the sensor, giving up after timeout if it is
+// positive. An abandoned read has its context cancelled, so no further
+// attempts or samples are started, but the attempt in flight cannot be
+// interrupted and keeps running in the background; until it finishes, further
+// reads of the sensor return errReadInProgress instead of stacking up on the
+// bus. The reading is timestamped when the read finished, or when it started
+// if stampAtStart.
+func (d *DHT22) readWithTimeout(timeout time.Duration, stampAtStart bool) error {
+	if !d.reading.CompareAndSwap(false, true) {
+		return errReadInProgress
+	}
+	if timeout <= 0 {
+		defer d.reading.Store(false)
+		return d.update(d.fetch(context.Background()), stampAtStart)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan readResult, 1)
+	go func() {
+		defer d.reading.Store(false)
+		done <- d.fetch(ctx)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return d.update(r, stampAtStart)
+	case <-timer.C:
+		return d.update(readResult{
+			elapsed: timeout,
+			err:     &ReadError{Pin: d.pin, Transient: true, Err: errReadTimeout},
+		}, stampAtStart)
+	}
+}
+
+func (d *DHT22) fetch(ctx context.Context) readResult {
+	d.RLock()
+	samples, gap, readFn := d.samples, d.sampleGap, d.readFn
+	d.RUnlock()
+
+	r := readResult{start: time.Now()}
+	var temps, hums []float32
+	for i := 0; i < samples && ctx.Err() == nil; i++ {
+		if i > 0 && !sleepCtx(ctx, gap) {
+			break
+		}
+		temperature, humidity, retried, err := readFn(ctx, d.pin)
+		r.retried += retried
+		if err != nil {
+			r.err = err
+			continue
+		}
+		temps = append(temps, temperature)
+		hums = append(hums, humidity)
+	}
+	r.elapsed = time.Since(r.start)
+	if len(temps) > 0 {
+		r.temperature, r.humidity, r.err = median(temps), median(hums), nil
+	}
+	return r
+}
+
+// sleepCtx sleeps for d, returning false if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+func median(values []float32) float32 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func (d *DHT22) update(r readResult, stampAtStart bool) error {
+	d.Lock()
+	defer d.Unlock()
+	d.Stats.record(r.elapsed, r.retried, r.err)
+	if r.err != nil {
+		fmt.Printf("Failed to get a successful reading after %d attempts: %v\n", r.retried, r.err)
+		d.LastError = &LastError{
+			Message:   r.err.Error(),
+			Time:      time.Now(),
+			Transient: IsTransient(r.err),
+		}
+		if !d.Timestamp.IsZero() {
+			d.Quality = d.gapQuality(time.Now())
+		}
+		return r.err
+	}
+	d.LastError = nil
+	temp := d.calibration.Temp.Apply(float64(r.temperature))
+	hum := d.calibration.Humidity.Apply(float64(r.humidity))
+	if d.warmUp > 0 {
+		d.warmUp--
+		fmt.Printf("Discarding warm-up reading from %s: %.1fC %.1f%%\n", d.Name, temp, hum)
+		return nil
+	}
+	if d.filter != nil && !d.filter.accept(temp, hum) {
+		d.Rejected++
+		d.Quality = QualityRejected
+		fmt.Printf("Rejected implausible reading from %s: %.1fC %.1f%%\n", d.Name, temp, hum)
+		return nil
+	}
+	d.Temp = temp
+	d.Humidity = hum
+	d.Quality = QualityOK
+	d.Timestamp = r.start.Add(r.elapsed)
+	if stampAtStart {
+		d.Timestamp = r.start
+	}
+	return nil
+}
+
+// gapQuality is the quality of carried-forward values at now under the
+// sensor's gap policy. The caller must hold the lock.
+func (d *DHT22) gapQuality(now time.Time) Quality {
+	if d.gap.Mode == config.GapLeave {
+		return QualityGap
+	}
+	if d.gap.MaxAge > 0 && now.Sub(d.Timestamp) > time.Duration(d.gap.MaxAge) {
+		return QualityGap
+	}
+	return QualityStale
+}
+
+func (d *DHT22) skip() {
+	d.Lock()
+	defer d.Unlock()
+	d.Stats.Skipped++
+}
+
+// apply updates the sensor in place from c, keeping its readings, stats and
+// filter history unless the filter settings changed.
+func (d *DHT22) apply(c *config.Dht22Config) {
+	d.Lock()
+	d.Name = c.Name
+	d.Location = c.Location
+	d.HeightCm = c.HeightCm
+	d.Position = c.Position
+	d.samples = max(c.Samples, 1)
+	d.decimals = defaultDecimals
+	if c.Decimals != nil {
+		d.decimals = max(*c.Decimals, 0)
+	}
+	d.gap = config.GapPolicy{}
+	if c.Gap != nil {
+		d.gap = *c.Gap
+	}
+	d.calibration = &config.Dht22Calibration{}
+	if c.Calibration != nil {
+		d.calibration = c.Calibration
+	}
+	unchanged := (d.filter == nil && c.Filter == nil) ||
+		(d.filter != nil && c.Filter != nil && *d.filter.cfg == *c.Filter)
+	d.Unlock()
+	if !unchanged {
+		d.SetFilter(c.Filter)
+	}
 }
 
 type Manager struct {
-	Sensors     map[int]*DHT22 `json:"dht22"`
-	stopReading chan struct{}
+	mu            sync.RWMutex
+	readFn        readFunc
+	driver        driver
+	retry         *config.RetryPolicy
+	readTimeout   time.Duration
+	cycleDeadline time.Duration
+	initialRead   bool
+	stampAtStart  bool
+	manualGap     time.Duration
+	lastManual    map[int]time.Time
+	now           func() time.Time
+	status        Status
+	trace         []CycleTrace
+	health        *health
+	Sensors       map[int]*DHT22 `json:"dht22"`
+	stopReading   chan struct{}
+	intervals     chan time.Duration
 }
 
 func NewManager() *Manager {
 	return &Manager{
-		Sensors: make(map[int]*DHT22),
+		initialRead: true,
+		manualGap:   config.Dht22ConversionGap,
+		lastManual:  make(map[int]time.Time),
+		now:         time.Now,
+		Sensors:     make(map[int]*DHT22),
+	}
+}
+
+// SetBackend selects the driver library, by its config.DhtBackend name, that
+// the next Reconcile gives every sensor. An empty name keeps d2r2.
+func (dm *Manager) SetBackend(name string) error {
+	d, err := lookupDriver(name)
+	if err != nil {
+		return err
 	}
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.driver = d
+	dm.readFn = dm.driverRead()
+	return nil
+}
+
+// SetRetryPolicy replaces the driver's fixed retries with p for every sensor
+// from the next Reconcile on. A nil policy restores the driver's own.
+func (dm *Manager) SetRetryPolicy(p *config.RetryPolicy) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.retry = p
+	dm.readFn = dm.driverRead()
 }
 
-func (dm *Manager) AddSensor(dht *DHT22) {
+// driverRead returns the readFunc for the selected driver and retry policy.
+// The caller must hold dm.mu.
+func (dm *Manager) driverRead() readFunc {
+	d := dm.driver
+	if d.read == nil {
+		d = drivers[config.DhtBackendD2r2]
+	}
+	if dm.retry == nil {
+		return d.read
+	}
+	return withRetry(d.once, *dm.retry)
+}
+
+// SetTimestampSource selects whether readings are timestamped when the read
+// started (config.TimestampReadStart) or when it completed
+// (config.TimestampReadComplete, the default). With retries a read can take
+// seconds, which matters when lining readings up with relay actions.
+func (dm *Manager) SetTimestampSource(source string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.stampAtStart = source == config.TimestampReadStart
+}
+
+// SetInitialRead controls whether StartReadCycle reads every sensor straight
+// away instead of waiting for the first tick. It is on by default so values
+// are available right after startup.
+func (dm *Manager) SetInitialRead(enabled bool) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.initialRead = enabled
+}
+
+// SetTimeouts bounds how long a single sensor read and a whole read cycle may
+// take. Sensors not reached before the cycle deadline are skipped until the
+// next tick. Zero disables either limit.
+func (dm *Manager) SetTimeouts(readTimeout, cycleDeadline time.Duration) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.readTimeout = readTimeout
+	dm.cycleDeadline = cycleDeadline
+}
+
+// AddSensor registers dht with the manager. It fails if another sensor is
+// already registered on the same pin; use ReplaceSensor to swap one out.
+func (dm *Manager) AddSensor(dht *DHT22) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if existing, ok := dm.Sensors[dht.pin]; ok {
+		return fmt.Errorf("dht22 pin %d is already registered to %q", dht.pin, existing.Name)
+	}
+	dm.Sensors[dht.pin] = dht
+	return nil
+}
+
+// ReplaceSensor registers dht, replacing any sensor already on its pin.
+func (dm *Manager) ReplaceSensor(dht *DHT22) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
 	dm.Sensors[dht.pin] = dht
 }
 
+// Reconcile brings the managed sensors in line with cfgs, typically after a
+// config reload. New pins are added, pins no longer configured are removed,
+// and existing sensors are updated in place so their readings and stats
+// survive. It is safe to call while the read cycle is running.
+func (dm *Manager) Reconcile(cfgs []*config.Dht22Config) error {
+	wanted := make(map[int]*config.Dht22Config, len(cfgs))
+	for _, c := range cfgs {
+		if _, ok := wanted[c.Pin]; ok {
+			return fmt.Errorf("dht22 pin %d is configured more than once", c.Pin)
+		}
+		wanted[c.Pin] = c
+	}
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	for pin := range dm.Sensors {
+		if _, ok := wanted[pin]; !ok {
+			delete(dm.Sensors, pin)
+			delete(dm.lastManual, pin)
+			if dm.health != nil {
+				delete(dm.health.outcomes, pin)
+				delete(dm.health.degraded, pin)
+			}
+		}
+	}
+	for pin, c := range wanted {
+		if sensor, ok := dm.Sensors[pin]; ok {
+			sensor.apply(c)
+			if dm.readFn != nil {
+				sensor.Lock()
+				sensor.readFn = dm.readFn
+				sensor.Unlock()
+			}
+			continue
+		}
+		sensor := NewDHT22FromConfig(c)
+		if dm.readFn != nil {
+			sensor.readFn = dm.readFn
+		}
+		dm.Sensors[pin] = sensor
+	}
+	return nil
+}
+
+func (dm *Manager) sensors() []*DHT22 {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	sensors := make([]*DHT22, 0, len(dm.Sensors))
+	for _, sensor := range dm.Sensors {
+		sensors = append(sensors, sensor)
+	}
+	sort.Slice(sensors, func(i, j int) bool { return sensors[i].pin < sensors[j].pin })
+	return sensors
+}
+
+// Readings returns the current state of every sensor in pin order.
+func (dm *Manager) Readings() []Reading {
+	sensors := dm.sensors()
+	readings := make([]Reading, 0, len(sensors))
+	for _, sensor := range sensors {
+		readings = append(readings, sensor.Reading())
+	}
+	return readings
+}
+
 func (dm *Manager) StartReadCycle(interval time.Duration) {
-	dm.stopReading = make(chan struct{})
+	stop, intervals := make(chan struct{}), make(chan time.Duration)
+	dm.mu.Lock()
+	dm.stopReading, dm.intervals = stop, intervals
+	initialRead := dm.initialRead
+	dm.status = Status{Running: true, Interval: interval}
+	dm.mu.Unlock()
 	go func() {
+		if initialRead {
+			dm.cycle()
+		}
 		ticker := time.NewTicker(interval)
-		select {
-		case <-dm.stopReading:
-			ticker.Stop()
-			return
-		case <-ticker.C:
-			for {
-				for _, sensor := range dm.Sensors {
-					sensor.read()
-				}
-				time.Sleep(interval)
+		defer ticker.Stop()
+		dm.scheduleNext(interval)
+		for {
+			select {
+			case <-stop:
+				return
+			case interval = <-intervals:
+				ticker.Reset(interval)
+				dm.mu.Lock()
+				dm.status.Interval = interval
+				dm.mu.Unlock()
+				dm.scheduleNext(interval)
+			case <-ticker.C:
+				dm.scheduleNext(interval)
+				dm.cycle()
 			}
 		}
 	}()
 }
 
+// SetInterval changes how often a running read cycle reads, typically after
+// a config reload. The next cycle is one new interval from now. It does
+// nothing when the cycle is not running or the interval is unchanged.
+func (dm *Manager) SetInterval(interval time.Duration) {
+	dm.mu.RLock()
+	status, stop, intervals := dm.status, dm.stopReading, dm.intervals
+	dm.mu.RUnlock()
+	if !status.Running || interval <= 0 || interval == status.Interval {
+		return
+	}
+	select {
+	case intervals <- interval:
+	case <-stop:
+	}
+}
+
+// cycle reads every sensor and records the pass in the manager's status.
+func (dm *Manager) cycle() {
+	read := dm.readAll()
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.status.LastCycle = dm.now()
+	dm.status.LastCycleReads = read
+}
+
+func (dm *Manager) scheduleNext(interval time.Duration) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.status.NextCycle = dm.now().Add(interval)
+}
+
+// readAll reads every enabled sensor and returns how many reads succeeded.
+func (dm *Manager) readAll() int {
+	dm.mu.RLock()
+	readTimeout, cycleDeadline, stampAtStart := dm.readTimeout, dm.cycleDeadline, dm.stampAtStart
+	dm.mu.RUnlock()
+
+	trace := CycleTrace{Start: time.Now()}
+	defer func() {
+		trace.End = time.Now()
+		dm.recordTrace(trace)
+	}()
+
+	var deadline time.Time
+	if cycleDeadline > 0 {
+		deadline = trace.Start.Add(cycleDeadline)
+	}
+	read := 0
+	sensors := dm.sensors()
+	for i, sensor := range sensors {
+		if !sensor.Enabled() {
+			continue
+		}
+		timeout := readTimeout
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				trace.Reads = append(trace.Reads, dm.skipRemaining(sensors[i:])...)
+				return read
+			}
+			if timeout <= 0 || remaining < timeout {
+				timeout = remaining
+			}
+		}
+		rt := ReadTrace{Pin: sensor.pin, Name: sensor.Reading().Name, Start: time.Now()}
+		err := sensor.readWithTimeout(timeout, stampAtStart)
+		rt.End = time.Now()
+		switch {
+		case err == nil:
+			read++
+			dm.observe(sensor, true)
+		case errors.Is(err, errReadInProgress):
+			sensor.skip()
+			rt.Skipped = true
+		default:
+			rt.Error = err.Error()
+			dm.observe(sensor, false)
+		}
+		if !rt.Skipped {
+			rt.Retries = sensor.ReadStats().LastRetries
+		}
+		trace.Reads = append(trace.Reads, rt)
+	}
+	return read
+}
+
+func (dm *Manager) skipRemaining(sensors []*DHT22) []ReadTrace {
+	var skipped []ReadTrace
+	now := time.Now()
+	for _, sensor := range sensors {
+		if sensor.Enabled() {
+			sensor.skip()
+			skipped = append(skipped, ReadTrace{Pin: sensor.pin, Name: sensor.Reading().Name, Start: now, End: now, Skipped: true})
+		}
+	}
+	fmt.Printf("Read cycle deadline reached, skipped %d sensors\n", len(skipped))
+	return skipped
+}
+
 func (dm *Manager) StopReadCycle() {
 	close(dm.stopReading)
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.status.Running = false
 }
 
 func (dm *Manager) String() string {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
 	data, _ := json.Marshal(dm)
 	return string(data)
 }
@@ -1,6 +1,9 @@
 package dht22
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,3 +32,74 @@ func TestMorusDht22(t *testing.T) {
 	}
 	t.Logf("temperature: %v, humidity: %v", temperature, humidity)
 }
+
+func TestDisabledSensorSkipped(t *testing.T) {
+	m := NewManager()
+	on := NewDHT22(4, "canopy", "tent")
+	off := NewDHT22(17, "unplugged", "tent")
+	for _, s := range []*DHT22{on, off} {
+		s.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+			return 24, 55, 0, nil
+		}
+		if err := m.AddSensor(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	off.SetEnabled(false)
+
+	m.readAll()
+
+	if on.ReadStats().Reads != 1 {
+		t.Errorf("enabled sensor reads = %d, want 1", on.ReadStats().Reads)
+	}
+	if stats := off.ReadStats(); stats.Reads != 0 || stats.Failures != 0 {
+		t.Errorf("disabled sensor was read: %+v", stats)
+	}
+	if !strings.Contains(m.String(), `"enabled":false`) {
+		t.Errorf("disabled sensor not flagged in listing: %s", m.String())
+	}
+
+	off.SetEnabled(true)
+	m.readAll()
+	if off.ReadStats().Reads != 1 {
+		t.Errorf("re-enabled sensor reads = %d, want 1", off.ReadStats().Reads)
+	}
+}
+
+func TestAddSensorDuplicatePin(t *testing.T) {
+	m := NewManager()
+	original := NewDHT22(4, "canopy", "tent")
+	if err := m.AddSensor(original); err != nil {
+		t.Fatalf("first AddSensor failed: %v", err)
+	}
+	if err := m.AddSensor(NewDHT22(4, "floor", "tent")); err == nil {
+		t.Fatal("expected an error adding a second sensor on pin 4")
+	}
+	if m.Sensors[4] != original {
+		t.Error("original sensor was overwritten")
+	}
+
+	replacement := NewDHT22(4, "floor", "tent")
+	m.ReplaceSensor(replacement)
+	if m.Sensors[4] != replacement {
+		t.Error("ReplaceSensor did not replace the sensor")
+	}
+}
+
+func TestReadTimeoutCancelsDriver(t *testing.T) {
+	d := NewDHT22(4, "canopy", "tent")
+	cancelled := make(chan struct{})
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return -1, -1, 0, ctx.Err()
+	}
+	if err := d.readWithTimeout(10*time.Millisecond, false); !errors.Is(err, errReadTimeout) {
+		t.Errorf("err = %v, want errReadTimeout", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("abandoned read was not cancelled")
+	}
+}
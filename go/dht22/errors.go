@@ -0,0 +1,75 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ReadError is returned when a sensor cannot be read. Transient errors such
+// as checksum mismatches are worth retrying; the rest point at a
+// misconfiguration such as a wrong pin or a missing device.
+type ReadError struct {
+	Pin       int
+	Transient bool
+	Err       error
+}
+
+func (e *ReadError) Error() string {
+	kind := "permanent"
+	if e.Transient {
+		kind = "transient"
+	}
+	return fmt.Sprintf("dht22 pin %d: %s read error: %v", e.Pin, kind, e.Err)
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err is a ReadError worth retrying.
+func IsTransient(err error) bool {
+	var re *ReadError
+	return errors.As(err, &re) && re.Transient
+}
+
+// permanentMessages are fragments of the errors go-dht reports when it cannot
+// set up the GPIO pin at all. Retrying those will never succeed.
+var permanentMessages = []string{
+	"failed to open",
+	"failed to export",
+	"failed to unexport",
+	"failed to set direction",
+	"permission denied",
+	"no such file",
+}
+
+// classify wraps an error from the DHT driver in a ReadError. The driver only
+// returns plain strings, so anything that is not recognisably a setup failure
+// is treated as a transient signalling error.
+func classify(pin int, err error) error {
+	if err == nil {
+		return nil
+	}
+	var re *ReadError
+	if errors.As(err, &re) {
+		return err
+	}
+	transient := true
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission),
+		errors.Is(err, context.Canceled):
+		transient = false
+	default:
+		msg := strings.ToLower(err.Error())
+		for _, m := range permanentMessages {
+			if strings.Contains(msg, m) {
+				transient = false
+				break
+			}
+		}
+	}
+	return &ReadError{Pin: pin, Transient: transient, Err: err}
+}
@@ -0,0 +1,69 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{
+			name:      "crc mismatch",
+			err:       errors.New("CRCs doesn't match: checksum from sensor(12) != calculated checksum(13=1+2+3+7)"),
+			transient: true,
+		},
+		{
+			name:      "truncated pulses",
+			err:       errors.New("Can't decode pulse array received from DHTxx sensor, since incorrect length: 40"),
+			transient: true,
+		},
+		{
+			name:      "missing value file",
+			err:       errors.New("Error during call C.dial_DHTxx_and_read(): failed to open pin 99 value for reading"),
+			transient: false,
+		},
+		{
+			name:      "missing device file",
+			err:       fmt.Errorf("open device: %w", &fs.PathError{Op: "open", Path: "/sys/class/gpio/gpio99/value", Err: fs.ErrNotExist}),
+			transient: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classify(4, tt.err)
+			var re *ReadError
+			if !errors.As(err, &re) {
+				t.Fatalf("classify returned %T, want *ReadError", err)
+			}
+			if re.Transient != tt.transient || IsTransient(err) != tt.transient {
+				t.Errorf("transient = %v, want %v", re.Transient, tt.transient)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("ReadError does not unwrap to the driver error")
+			}
+		})
+	}
+}
+
+func TestClassifyNil(t *testing.T) {
+	if err := classify(4, nil); err != nil {
+		t.Errorf("classify(nil) = %v, want nil", err)
+	}
+}
+
+func TestReadReturnsError(t *testing.T) {
+	d := NewDHT22(4, "canopy", "tent")
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		return -1, -1, 3, classify(pin, errors.New("CRCs doesn't match"))
+	}
+	if err := d.read(); !IsTransient(err) {
+		t.Errorf("read() = %v, want a transient ReadError", err)
+	}
+}
@@ -0,0 +1,88 @@
+package dht22
+
+import (
+	"math"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+const defaultFilterWindow = 5
+
+// filter keeps a short history of accepted readings so that spikes can be
+// compared against the recent average.
+type filter struct {
+	cfg    *config.Dht22Filter
+	window int
+	temps  []float64
+	hums   []float64
+	streak int
+}
+
+func newFilter(cfg *config.Dht22Filter) *filter {
+	window := cfg.Window
+	if window <= 0 {
+		window = defaultFilterWindow
+	}
+	return &filter{
+		cfg:    cfg,
+		window: window,
+	}
+}
+
+// accept reports whether the reading should be kept. A reading outside the
+// configured range is always rejected. A reading that deviates too far from
+// the recent average is rejected unless it keeps happening for a full window,
+// at which point it is treated as a real change and becomes the new baseline.
+func (f *filter) accept(temp, humidity float64) bool {
+	if !inRange(temp, f.cfg.MinTemp, f.cfg.MaxTemp) || !inRange(humidity, f.cfg.MinHumidity, f.cfg.MaxHumidity) {
+		return false
+	}
+	if f.deviates(temp, humidity) {
+		f.streak++
+		if f.streak < f.window {
+			return false
+		}
+		f.temps = f.temps[:0]
+		f.hums = f.hums[:0]
+	}
+	f.streak = 0
+	f.temps = push(f.temps, temp, f.window)
+	f.hums = push(f.hums, humidity, f.window)
+	return true
+}
+
+func (f *filter) deviates(temp, humidity float64) bool {
+	if len(f.temps) == 0 {
+		return false
+	}
+	if f.cfg.MaxTempDelta > 0 && math.Abs(temp-average(f.temps)) > f.cfg.MaxTempDelta {
+		return true
+	}
+	if f.cfg.MaxHumidityDelta > 0 && math.Abs(humidity-average(f.hums)) > f.cfg.MaxHumidityDelta {
+		return true
+	}
+	return false
+}
+
+func inRange(v, lo, hi float64) bool {
+	if hi <= lo {
+		return true
+	}
+	return v >= lo && v <= hi
+}
+
+func push(values []float64, v float64, limit int) []float64 {
+	values = append(values, v)
+	if len(values) > limit {
+		values = values[len(values)-limit:]
+	}
+	return values
+}
+
+func average(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
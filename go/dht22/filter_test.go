@@ -0,0 +1,95 @@
+package dht22
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+type reading struct {
+	temp, humidity float32
+}
+
+func seriesReader(series []reading) readFunc {
+	i := 0
+	return func(ctx context.Context, pin int) (float32, float32, int, error) {
+		r := series[i]
+		i++
+		return r.temp, r.humidity, 0, nil
+	}
+}
+
+func TestFilterRejectsSpikes(t *testing.T) {
+	series := []reading{
+		{24.0, 55.0},
+		{24.2, 55.5},
+		{74.0, 55.0}, // temperature spike
+		{24.3, 0.0},  // humidity dropout
+		{24.5, 56.0},
+		{125.0, 56.0}, // out of range
+		{25.0, 57.0},
+	}
+	want := []struct {
+		temp, humidity float64
+		rejected       uint64
+	}{
+		{24.0, 55.0, 0},
+		{24.2, 55.5, 0},
+		{24.2, 55.5, 1},
+		{24.2, 55.5, 2},
+		{24.5, 56.0, 2},
+		{24.5, 56.0, 3},
+		{25.0, 57.0, 3},
+	}
+
+	d := NewDHT22(4, "canopy", "tent")
+	d.readFn = seriesReader(series)
+	d.SetFilter(&config.Dht22Filter{
+		MinTemp:          -40,
+		MaxTemp:          80,
+		MinHumidity:      0,
+		MaxHumidity:      100,
+		MaxTempDelta:     5,
+		MaxHumidityDelta: 15,
+		Window:           5,
+	})
+
+	for i, w := range want {
+		d.read()
+		if float64(float32(w.temp)) != d.Temp || float64(float32(w.humidity)) != d.Humidity {
+			t.Errorf("reading %d: got %v/%v, want %v/%v", i, d.Temp, d.Humidity, w.temp, w.humidity)
+		}
+		if d.Rejected != w.rejected {
+			t.Errorf("reading %d: rejected = %d, want %d", i, d.Rejected, w.rejected)
+		}
+	}
+}
+
+func TestFilterAdoptsSustainedChange(t *testing.T) {
+	f := newFilter(&config.Dht22Filter{MaxTempDelta: 2, Window: 3})
+	if !f.accept(20, 50) {
+		t.Fatal("first reading rejected")
+	}
+	for i := 0; i < 2; i++ {
+		if f.accept(30, 50) {
+			t.Fatalf("step %d accepted before a full window", i)
+		}
+	}
+	if !f.accept(30, 50) {
+		t.Fatal("sustained change was not adopted")
+	}
+	if !f.accept(30.5, 50) {
+		t.Fatal("reading near new baseline rejected")
+	}
+}
+
+func TestFilterDisabled(t *testing.T) {
+	d := NewDHT22(4, "canopy", "tent")
+	d.readFn = seriesReader([]reading{{20, 50}, {70, 0}})
+	d.read()
+	d.read()
+	if d.Temp != 70 || d.Humidity != 0 || d.Rejected != 0 {
+		t.Errorf("unfiltered sensor got %v/%v rejected %d", d.Temp, d.Humidity, d.Rejected)
+	}
+}
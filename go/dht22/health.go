@@ -0,0 +1,115 @@
+package dht22
+
+import (
+	"fmt"
+	"sort"
+)
+
+// health tracks the outcome of each sensor's recent reads.
+type health struct {
+	window   int
+	minRate  float64
+	outcomes map[int][]bool
+	degraded map[int]bool
+}
+
+// Diagnostic is a sensor's recent read health.
+type Diagnostic struct {
+	Pin         int     `json:"pin"`
+	Name        string  `json:"name"`
+	Samples     int     `json:"samples"`
+	SuccessRate float64 `json:"success_rate"`
+	Degraded    bool    `json:"degraded"`
+}
+
+// SetHealthCheck makes the manager track the success rate of each sensor's
+// last window reads, warning when it falls below minRate. Nothing is flagged
+// until a sensor has a full window of reads. Calling it again with the same
+// settings keeps the history.
+func (dm *Manager) SetHealthCheck(window int, minRate float64) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if h := dm.health; h != nil && h.window == window && h.minRate == minRate {
+		return
+	}
+	if window <= 0 {
+		dm.health = nil
+		return
+	}
+	dm.health = &health{
+		window:   window,
+		minRate:  minRate,
+		outcomes: make(map[int][]bool),
+		degraded: make(map[int]bool),
+	}
+}
+
+func (dm *Manager) observe(sensor *DHT22, ok bool) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	h := dm.health
+	// A sensor removed by Reconcile while it was being read is not tracked
+	// again.
+	if h == nil || dm.Sensors[sensor.pin] != sensor {
+		return
+	}
+	outcomes := append(h.outcomes[sensor.pin], ok)
+	if len(outcomes) > h.window {
+		outcomes = outcomes[len(outcomes)-h.window:]
+	}
+	h.outcomes[sensor.pin] = outcomes
+	if len(outcomes) < h.window {
+		return
+	}
+
+	rate := successRate(outcomes)
+	name := sensor.Reading().Name
+	switch degraded := rate < h.minRate; {
+	case degraded && !h.degraded[sensor.pin]:
+		fmt.Printf("Warning: %s succeeded on %.0f%% of its last %d reads, check its wiring\n", name, rate*100, h.window)
+	case !degraded && h.degraded[sensor.pin]:
+		fmt.Printf("%s has recovered to %.0f%% successful reads\n", name, rate*100)
+	}
+	h.degraded[sensor.pin] = rate < h.minRate
+}
+
+// Diagnostics returns the recent read health of every tracked sensor in pin
+// order, or nil when the health check is off.
+func (dm *Manager) Diagnostics() []Diagnostic {
+	sensors := dm.sensors()
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	h := dm.health
+	if h == nil {
+		return nil
+	}
+	var diagnostics []Diagnostic
+	for _, sensor := range sensors {
+		outcomes, ok := h.outcomes[sensor.pin]
+		if !ok {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Pin:         sensor.pin,
+			Name:        sensor.Reading().Name,
+			Samples:     len(outcomes),
+			SuccessRate: successRate(outcomes),
+			Degraded:    h.degraded[sensor.pin],
+		})
+	}
+	sort.Slice(diagnostics, func(i, j int) bool { return diagnostics[i].Pin < diagnostics[j].Pin })
+	return diagnostics
+}
+
+func successRate(outcomes []bool) float64 {
+	if len(outcomes) == 0 {
+		return 0
+	}
+	n := 0
+	for _, ok := range outcomes {
+		if ok {
+			n++
+		}
+	}
+	return float64(n) / float64(len(outcomes))
+}
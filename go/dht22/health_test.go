@@ -0,0 +1,108 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestHealthCheckDegrades(t *testing.T) {
+	// Healthy for ten reads, then every other read fails.
+	var pattern []bool
+	for i := 0; i < 10; i++ {
+		pattern = append(pattern, true)
+	}
+	for i := 0; i < 10; i++ {
+		pattern = append(pattern, i%2 == 1)
+	}
+
+	m := NewManager()
+	m.SetHealthCheck(10, 0.8)
+	s := NewDHT22(4, "canopy", "tent")
+	i := 0
+	s.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		ok := pattern[i]
+		i++
+		if !ok {
+			return -1, -1, 0, classify(pin, errors.New("CRCs doesn't match"))
+		}
+		return 24, 55, 0, nil
+	}
+	if err := m.AddSensor(s); err != nil {
+		t.Fatal(err)
+	}
+
+	degradedAt := -1
+	for read := range pattern {
+		m.readAll()
+		d := m.Diagnostics()
+		if len(d) != 1 {
+			t.Fatalf("got %d diagnostics, want 1", len(d))
+		}
+		if d[0].Degraded && degradedAt < 0 {
+			degradedAt = read
+		}
+	}
+	// The third failure (read 14) takes the last ten reads to 70%.
+	if degradedAt != 14 {
+		t.Errorf("degraded at read %d, want 14", degradedAt)
+	}
+	if d := m.Diagnostics()[0]; d.SuccessRate != 0.5 || d.Samples != 10 {
+		t.Errorf("final diagnostic = %+v, want 50%% over 10 reads", d)
+	}
+}
+
+func TestHealthCheckOff(t *testing.T) {
+	m, _ := newCycleManager(t, 0)
+	m.readAll()
+	if d := m.Diagnostics(); d != nil {
+		t.Errorf("diagnostics = %+v with the health check off", d)
+	}
+}
+
+func TestReconcileForgetsHealth(t *testing.T) {
+	m := NewManager()
+	m.SetHealthCheck(2, 0.8)
+	m.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		return -1, -1, 0, classify(pin, errors.New("CRCs doesn't match"))
+	}
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Name: "canopy"}}); err != nil {
+		t.Fatal(err)
+	}
+	m.readAll()
+	m.readAll()
+	if d := m.Diagnostics(); len(d) != 1 || !d[0].Degraded {
+		t.Fatalf("diagnostics = %+v, want pin 4 degraded", d)
+	}
+
+	if err := m.Reconcile(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.health.outcomes) != 0 || len(m.health.degraded) != 0 {
+		t.Errorf("health still tracks removed pins: %v %v", m.health.outcomes, m.health.degraded)
+	}
+	m.readFn = constReader
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Name: "canopy"}}); err != nil {
+		t.Fatal(err)
+	}
+	m.readAll()
+	if d := m.Diagnostics(); len(d) != 1 || d[0].Degraded || d[0].Samples != 1 {
+		t.Errorf("diagnostics = %+v, want a fresh history for the re-added sensor", d)
+	}
+}
+
+func TestSetHealthCheckKeepsHistory(t *testing.T) {
+	m, _ := newCycleManager(t, 0)
+	m.SetHealthCheck(10, 0.8)
+	m.readAll()
+	m.SetHealthCheck(10, 0.8)
+	if d := m.Diagnostics(); len(d) != 1 || d[0].Samples != 1 {
+		t.Errorf("diagnostics = %+v, want the history kept for unchanged settings", d)
+	}
+	m.SetHealthCheck(5, 0.8)
+	if d := m.Diagnostics(); len(d) != 0 {
+		t.Errorf("diagnostics = %+v, want a fresh history for a new window", d)
+	}
+}
@@ -0,0 +1,69 @@
+package dht22
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+const (
+	temperatureUnit = "C"
+	humidityUnit    = "%"
+	// defaultDecimals is the meaningful resolution of a DHT22 reading (0.1).
+	defaultDecimals = 1
+)
+
+// Measurement is a self-describing value as exposed in the JSON output.
+type Measurement struct {
+	Value     float64 `json:"value"`
+	Unit      string  `json:"unit"`
+	Precision float64 `json:"precision"`
+}
+
+func newMeasurement(v float64, unit string, decimals int) *Measurement {
+	scale := math.Pow10(decimals)
+	return &Measurement{
+		Value:     math.Round(v*scale) / scale,
+		Unit:      unit,
+		Precision: 1 / scale,
+	}
+}
+
+// MarshalJSON rounds the readings to the configured number of decimals and
+// tags them with their units. The struct keeps full precision. During a gap
+// the readings are null.
+func (d *DHT22) MarshalJSON() ([]byte, error) {
+	r := d.Reading()
+	temperature := newMeasurement(r.Temp, temperatureUnit, r.Decimals)
+	humidity := newMeasurement(r.Humidity, humidityUnit, r.Decimals)
+	if r.Quality == QualityGap {
+		temperature, humidity = nil, nil
+	}
+	return json.Marshal(struct {
+		Name        string       `json:"name"`
+		Location    string       `json:"location"`
+		HeightCm    int          `json:"height_cm,omitempty"`
+		Position    string       `json:"position,omitempty"`
+		Enabled     bool         `json:"enabled"`
+		Temperature *Measurement `json:"temperature"`
+		Humidity    *Measurement `json:"humidity"`
+		Timestamp   time.Time    `json:"timestamp"`
+		Quality     Quality      `json:"quality,omitempty"`
+		Rejected    uint64       `json:"rejected"`
+		Stats       Stats        `json:"stats"`
+		LastError   *LastError   `json:"last_error"`
+	}{
+		Name:        r.Name,
+		Location:    r.Location,
+		HeightCm:    r.HeightCm,
+		Position:    r.Position,
+		Enabled:     r.Enabled,
+		Temperature: temperature,
+		Humidity:    humidity,
+		Timestamp:   r.Timestamp,
+		Quality:     r.Quality,
+		Rejected:    r.Rejected,
+		Stats:       r.Stats,
+		LastError:   r.LastError,
+	})
+}
@@ -0,0 +1,150 @@
+package dht22
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestMarshalJSONUnits(t *testing.T) {
+	d := NewDHT22(4, "canopy", "tent")
+	d.Temp = 24.3456
+	d.Humidity = 55.05001
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("failed to marshal sensor: %v", err)
+	}
+	var got struct {
+		Name        string      `json:"name"`
+		Temperature Measurement `json:"temperature"`
+		Humidity    Measurement `json:"humidity"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", data, err)
+	}
+
+	want := Measurement{Value: 24.3, Unit: "C", Precision: 0.1}
+	if got.Temperature != want {
+		t.Errorf("temperature = %+v, want %+v", got.Temperature, want)
+	}
+	want = Measurement{Value: 55.1, Unit: "%", Precision: 0.1}
+	if got.Humidity != want {
+		t.Errorf("humidity = %+v, want %+v", got.Humidity, want)
+	}
+	if d.Temp != 24.3456 {
+		t.Errorf("marshal changed the stored temperature to %v", d.Temp)
+	}
+}
+
+func TestMarshalJSONLastError(t *testing.T) {
+	fail := true
+	d := NewDHT22(4, "canopy", "tent")
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		if fail {
+			return -1, -1, 3, classify(pin, errors.New("CRCs doesn't match"))
+		}
+		return 24, 55, 0, nil
+	}
+
+	lastError := func() *LastError {
+		t.Helper()
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatalf("failed to marshal sensor: %v", err)
+		}
+		var got struct {
+			LastError *LastError `json:"last_error"`
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", data, err)
+		}
+		return got.LastError
+	}
+
+	before := time.Now()
+	d.read()
+	got := lastError()
+	if got == nil {
+		t.Fatal("last_error missing after a failed read")
+	}
+	if !got.Transient || got.Message == "" || got.Time.Before(before.Truncate(time.Second)) {
+		t.Errorf("unexpected last_error %+v", got)
+	}
+
+	fail = false
+	d.read()
+	if got := lastError(); got != nil {
+		t.Errorf("last_error = %+v after a successful read, want it cleared", got)
+	}
+}
+
+func TestMarshalJSONPlacement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	raw := `{"dht22": [{"pin": 4, "name": "canopy", "location": "tent", "height_cm": 150, "position": "canopy"}]}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	data, err := json.Marshal(NewDHT22FromConfig(cfg.Dht22[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		HeightCm int    `json:"height_cm"`
+		Position string `json:"position"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", data, err)
+	}
+	if got.HeightCm != 150 || got.Position != "canopy" {
+		t.Errorf("placement = %+v, want 150cm canopy", got)
+	}
+}
+
+func TestMarshalJSONDecimals(t *testing.T) {
+	d := NewDHT22(4, "canopy", "tent")
+	// Neither value is exact in binary, so unrounded output would show
+	// float noise such as 24.300000000000001.
+	d.Temp = float64(float32(24.3))
+	d.Humidity = float64(float32(55.17))
+
+	for _, tc := range []struct {
+		decimals  int
+		temp, hum string
+	}{
+		{1, `"value":24.3`, `"value":55.2`},
+		{2, `"value":24.3`, `"value":55.17`},
+		{0, `"value":24`, `"value":55`},
+	} {
+		d.SetDecimals(tc.decimals)
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Temperature json.RawMessage `json:"temperature"`
+			Humidity    json.RawMessage `json:"humidity"`
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", data, err)
+		}
+		if !strings.HasPrefix(string(got.Temperature), "{"+tc.temp+",") {
+			t.Errorf("%d decimals: temperature = %s, want %s", tc.decimals, got.Temperature, tc.temp)
+		}
+		if !strings.HasPrefix(string(got.Humidity), "{"+tc.hum+",") {
+			t.Errorf("%d decimals: humidity = %s, want %s", tc.decimals, got.Humidity, tc.hum)
+		}
+	}
+}
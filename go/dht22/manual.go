@@ -0,0 +1,44 @@
+package dht22
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownSensor = errors.New("no such sensor")
+	ErrRateLimited   = errors.New("sensor was read manually too recently")
+)
+
+// ReadNow reads the named sensor immediately, outside the read cycle, and
+// returns the fresh reading. It is meant for checking wiring. A read already
+// running for the sensor, from the cycle or another caller, is not doubled up
+// on and returns an error instead, and manual reads of one sensor are limited
+// to one per conversion interval to protect the bus.
+func (dm *Manager) ReadNow(name string) (Reading, error) {
+	var sensor *DHT22
+	for _, s := range dm.sensors() {
+		if s.Reading().Name == name {
+			sensor = s
+			break
+		}
+	}
+	if sensor == nil {
+		return Reading{}, fmt.Errorf("%w: %q", ErrUnknownSensor, name)
+	}
+
+	dm.mu.Lock()
+	now := dm.now()
+	if last, ok := dm.lastManual[sensor.pin]; ok && now.Sub(last) < dm.manualGap {
+		dm.mu.Unlock()
+		return Reading{}, fmt.Errorf("%w, retry in %v", ErrRateLimited, dm.manualGap-now.Sub(last))
+	}
+	dm.lastManual[sensor.pin] = now
+	readTimeout, stampAtStart := dm.readTimeout, dm.stampAtStart
+	dm.mu.Unlock()
+
+	if err := sensor.readWithTimeout(readTimeout, stampAtStart); err != nil {
+		return Reading{}, err
+	}
+	return sensor.Reading(), nil
+}
@@ -0,0 +1,68 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestReadNow(t *testing.T) {
+	m := NewManager()
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	m.now = clock.Now
+	s := NewDHT22(4, "canopy", "tent")
+	s.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		return 24.5, 60, 0, nil
+	}
+	if err := m.AddSensor(s); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := m.ReadNow("canopy")
+	if err != nil {
+		t.Fatalf("ReadNow: %v", err)
+	}
+	if r.Temp != 24.5 || r.Humidity != 60 {
+		t.Errorf("got %v/%v, want 24.5/60", r.Temp, r.Humidity)
+	}
+
+	if _, err := m.ReadNow("canopy"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("second read err = %v, want ErrRateLimited", err)
+	}
+	clock.now = clock.now.Add(config.Dht22ConversionGap)
+	if _, err := m.ReadNow("canopy"); err != nil {
+		t.Errorf("read after the gap: %v", err)
+	}
+	if _, err := m.ReadNow("roots"); !errors.Is(err, ErrUnknownSensor) {
+		t.Errorf("unknown sensor err = %v, want ErrUnknownSensor", err)
+	}
+}
+
+func TestReadNowDuringCycle(t *testing.T) {
+	m, sensors := newCycleManager(t, 50*time.Millisecond)
+	calls := 0
+	sensors[0].readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		calls++
+		time.Sleep(50 * time.Millisecond)
+		return 24, 55, 0, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.readAll()
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_, err := m.ReadNow(sensors[0].Name)
+	<-done
+
+	if !errors.Is(err, errReadInProgress) {
+		t.Errorf("ReadNow during a cycle read err = %v, want errReadInProgress", err)
+	}
+	if calls != 1 {
+		t.Errorf("driver called %d times, want the reads serialized to 1", calls)
+	}
+}
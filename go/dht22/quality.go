@@ -0,0 +1,19 @@
+package dht22
+
+// Quality flags what a sensor's current values are. It is empty until the
+// first reading is accepted.
+type Quality string
+
+const (
+	// QualityOK means the values came from the latest read.
+	QualityOK Quality = "ok"
+	// QualityRejected means the latest read was rejected by the filter and
+	// the values are the last accepted ones.
+	QualityRejected Quality = "rejected"
+	// QualityStale means the latest read failed and the values are carried
+	// forward from an earlier one.
+	QualityStale Quality = "stale"
+	// QualityGap means reads have been failing for longer than the gap
+	// policy carries values forward, so the sensor reports no values.
+	QualityGap Quality = "gap"
+)
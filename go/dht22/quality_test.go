@@ -0,0 +1,98 @@
+package dht22
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestQuality(t *testing.T) {
+	series := []struct {
+		reading
+		err  error
+		want Quality
+	}{
+		{reading{24, 55}, nil, QualityOK},
+		{reading{70, 55}, nil, QualityRejected},
+		{reading{}, errors.New("CRCs doesn't match"), QualityStale},
+		{reading{24.5, 56}, nil, QualityOK},
+	}
+	i := 0
+	d := NewDHT22(4, "canopy", "tent")
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		s := series[i]
+		return s.temp, s.humidity, 0, s.err
+	}
+	d.SetFilter(&config.Dht22Filter{MaxTempDelta: 5})
+
+	if q := d.Reading().Quality; q != "" {
+		t.Errorf("quality before any reading = %q, want empty", q)
+	}
+	for ; i < len(series); i++ {
+		d.read()
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Quality Quality `json:"quality"`
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", data, err)
+		}
+		if got.Quality != series[i].want {
+			t.Errorf("reading %d: quality = %q, want %q", i, got.Quality, series[i].want)
+		}
+	}
+}
+
+func TestGapPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy *config.GapPolicy
+		gap    time.Duration
+		want   Quality
+	}{
+		{"default short", nil, time.Minute, QualityStale},
+		{"default long", nil, 24 * time.Hour, QualityStale},
+		{"carry within limit", &config.GapPolicy{Mode: config.GapCarryForward, MaxAge: config.Duration(5 * time.Minute)}, time.Minute, QualityStale},
+		{"carry past limit", &config.GapPolicy{Mode: config.GapCarryForward, MaxAge: config.Duration(5 * time.Minute)}, 10 * time.Minute, QualityGap},
+		{"leave gap", &config.GapPolicy{Mode: config.GapLeave}, time.Second, QualityGap},
+	} {
+		fail := false
+		d := NewDHT22(4, "canopy", "tent")
+		d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+			if fail {
+				return -1, -1, 0, classify(pin, errors.New("CRCs doesn't match"))
+			}
+			return 24, 55, 0, nil
+		}
+		d.SetGapPolicy(tc.policy)
+
+		d.read()
+		d.Timestamp = d.Timestamp.Add(-tc.gap)
+		fail = true
+		d.read()
+
+		if q := d.Reading().Quality; q != tc.want {
+			t.Errorf("%s: quality = %q, want %q", tc.name, q, tc.want)
+		}
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Temperature *Measurement `json:"temperature"`
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", data, err)
+		}
+		if carried := got.Temperature != nil; carried != (tc.want == QualityStale) {
+			t.Errorf("%s: temperature = %+v in %s", tc.name, got.Temperature, tc.want)
+		}
+	}
+}
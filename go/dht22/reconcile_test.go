@@ -0,0 +1,121 @@
+package dht22
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func constReader(ctx context.Context, pin int) (float32, float32, int, error) {
+	return 24, 55, 0, nil
+}
+
+func TestReconcileWhileReading(t *testing.T) {
+	m := NewManager()
+	m.readFn = constReader
+	err := m.Reconcile([]*config.Dht22Config{
+		{Pin: 4, Name: "canopy", Location: "tent"},
+		{Pin: 17, Name: "floor", Location: "tent"},
+	})
+	if err != nil {
+		t.Fatalf("initial reconcile failed: %v", err)
+	}
+	kept := m.Sensors[4]
+
+	m.StartReadCycle(5 * time.Millisecond)
+	defer m.StopReadCycle()
+	time.Sleep(30 * time.Millisecond)
+
+	before := kept.ReadStats().Reads
+	if before == 0 {
+		t.Fatal("read cycle did not read the sensor")
+	}
+
+	err = m.Reconcile([]*config.Dht22Config{
+		{Pin: 4, Name: "top of canopy", Location: "tent"},
+		{Pin: 22, Name: "ambient", Location: "room"},
+	})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	m.mu.RLock()
+	got4, got17, got22 := m.Sensors[4], m.Sensors[17], m.Sensors[22]
+	m.mu.RUnlock()
+
+	if got4 != kept {
+		t.Fatal("existing sensor was replaced instead of updated")
+	}
+	kept.RLock()
+	name := kept.Name
+	kept.RUnlock()
+	if name != "top of canopy" {
+		t.Errorf("name = %q, want it updated", name)
+	}
+	if after := kept.ReadStats().Reads; after <= before {
+		t.Errorf("reads went from %d to %d, want stats kept and still counting", before, after)
+	}
+	if got17 != nil {
+		t.Error("removed sensor is still managed")
+	}
+	if got22 == nil || got22.ReadStats().Reads == 0 {
+		t.Error("added sensor is not being read")
+	}
+}
+
+func TestReconcileKeepsFilterHistory(t *testing.T) {
+	filter := &config.Dht22Filter{MaxTempDelta: 5}
+	m := NewManager()
+	m.readFn = constReader
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Filter: filter}}); err != nil {
+		t.Fatal(err)
+	}
+	s := m.Sensors[4]
+	s.read()
+	f := s.filter
+
+	same := *filter
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Name: "renamed", Filter: &same}}); err != nil {
+		t.Fatal(err)
+	}
+	if s.filter != f {
+		t.Error("unchanged filter was reset")
+	}
+
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Filter: &config.Dht22Filter{MaxTempDelta: 2}}}); err != nil {
+		t.Fatal(err)
+	}
+	if s.filter == f || s.filter.cfg.MaxTempDelta != 2 {
+		t.Error("changed filter was not applied")
+	}
+}
+
+func TestReconcileDuplicatePin(t *testing.T) {
+	m := NewManager()
+	err := m.Reconcile([]*config.Dht22Config{{Pin: 4}, {Pin: 4}})
+	if err == nil {
+		t.Fatal("expected a duplicate pin error")
+	}
+}
+
+func TestReconcileAppliesRetryPolicyToExistingSensors(t *testing.T) {
+	m := NewManager()
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Name: "canopy"}}); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	m.driver = driver{name: "fake", read: constReader, once: failingReader(1, &calls)}
+	m.SetRetryPolicy(&config.RetryPolicy{MaxAttempts: 2})
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Name: "canopy"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Sensors[4].read(); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want the retry policy applied to the existing sensor", calls)
+	}
+}
@@ -0,0 +1,59 @@
+package dht22
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// withRetry wraps a single-attempt readFunc in policy p. Transient failures
+// are retried up to MaxAttempts in total, each delay doubling from BaseDelay
+// plus up to Jitter of random spread so sensors on a shared bus do not retry
+// in lockstep. No retry is started that would end past Budget, assuming it
+// takes as long as the attempt before it, or once ctx is done. Permanent
+// errors are returned at once.
+func withRetry(once readFunc, p config.RetryPolicy) readFunc {
+	return retryWithClock(once, p, time.Now, time.Sleep)
+}
+
+func retryWithClock(once readFunc, p config.RetryPolicy, now func() time.Time, sleep func(time.Duration)) readFunc {
+	attempts := max(p.MaxAttempts, 1)
+	return func(ctx context.Context, pin int) (float32, float32, int, error) {
+		start := now()
+		delay := time.Duration(p.BaseDelay)
+		var last time.Duration
+		var err error
+		for i := 0; i < attempts; i++ {
+			if i > 0 {
+				if ctx.Err() != nil {
+					return -1, -1, i - 1, err
+				}
+				wait := delay
+				if p.Jitter > 0 {
+					wait += rand.N(time.Duration(p.Jitter))
+				}
+				if p.Budget > 0 && now().Sub(start)+wait+last > time.Duration(p.Budget) {
+					return -1, -1, i - 1, err
+				}
+				sleep(wait)
+				if ctx.Err() != nil {
+					return -1, -1, i - 1, err
+				}
+				delay *= 2
+			}
+			attemptStart := now()
+			var temperature, humidity float32
+			temperature, humidity, _, err = once(ctx, pin)
+			last = now().Sub(attemptStart)
+			if err == nil {
+				return temperature, humidity, i, nil
+			}
+			if !IsTransient(err) {
+				return -1, -1, i, err
+			}
+		}
+		return -1, -1, attempts - 1, err
+	}
+}
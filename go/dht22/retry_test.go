@@ -0,0 +1,128 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// failingReader fails the first failures calls with a transient error.
+func failingReader(failures int, calls *int) readFunc {
+	return func(ctx context.Context, pin int) (float32, float32, int, error) {
+		*calls++
+		if *calls <= failures {
+			return -1, -1, 0, classify(pin, errors.New("CRCs doesn't match"))
+		}
+		return 24, 55, 0, nil
+	}
+}
+
+func TestRetryRecovers(t *testing.T) {
+	calls := 0
+	read := withRetry(failingReader(2, &calls), config.RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   config.Duration(time.Millisecond),
+		Jitter:      config.Duration(time.Millisecond),
+	})
+	temp, _, retried, err := read(context.Background(), 4)
+	if err != nil || temp != 24 {
+		t.Fatalf("got %v, %v; want a reading after two retries", temp, err)
+	}
+	if calls != 3 || retried != 2 {
+		t.Errorf("calls = %d, retried = %d; want 3 and 2", calls, retried)
+	}
+}
+
+func TestRetryAttemptCap(t *testing.T) {
+	calls := 0
+	read := withRetry(failingReader(10, &calls), config.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   config.Duration(time.Millisecond),
+	})
+	if _, _, _, err := read(context.Background(), 4); !IsTransient(err) {
+		t.Errorf("err = %v, want the last transient error", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want the 3 attempt cap", calls)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	advance := func(d time.Duration) { clock.now = clock.now.Add(d) }
+	calls := 0
+	failing := failingReader(10, &calls)
+	// Each attempt takes 8ms.
+	slow := func(ctx context.Context, pin int) (float32, float32, int, error) {
+		advance(8 * time.Millisecond)
+		return failing(ctx, pin)
+	}
+	start := clock.Now()
+	read := retryWithClock(slow, config.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   config.Duration(10 * time.Millisecond),
+		Budget:      config.Duration(60 * time.Millisecond),
+	}, clock.Now, advance)
+
+	_, _, retried, err := read(context.Background(), 4)
+	if !IsTransient(err) {
+		t.Errorf("err = %v, want the last transient error", err)
+	}
+	// Attempts end at 8ms and 26ms. A third, after a 20ms wait, would end at
+	// 54ms and fits; a fourth, after 40ms more, would not.
+	if calls != 3 || retried != 2 {
+		t.Errorf("calls = %d, retried = %d; want 3 and 2 within the budget", calls, retried)
+	}
+	if elapsed := clock.Now().Sub(start); elapsed > 60*time.Millisecond {
+		t.Errorf("read took %v, over the 60ms budget", elapsed)
+	}
+}
+
+func TestRetryStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	read := withRetry(func(ctx context.Context, pin int) (float32, float32, int, error) {
+		calls++
+		return -1, -1, 0, classify(pin, errors.New("failed to open /dev/gpiomem"))
+	}, config.RetryPolicy{MaxAttempts: 5})
+	if _, _, _, err := read(context.Background(), 4); err == nil || IsTransient(err) {
+		t.Errorf("err = %v, want a permanent error", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want no retries for a permanent error", calls)
+	}
+}
+
+func TestManagerRetryPolicy(t *testing.T) {
+	calls := 0
+	m := NewManager()
+	m.driver = driver{name: "fake", read: constReader, once: failingReader(1, &calls)}
+	m.SetRetryPolicy(&config.RetryPolicy{MaxAttempts: 2})
+	if err := m.Reconcile([]*config.Dht22Config{{Pin: 4, Name: "canopy"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Sensors[4].read(); err != nil {
+		t.Fatalf("read under the retry policy failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("single-attempt reader called %d times, want 2", calls)
+	}
+}
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	failing := failingReader(10, &calls)
+	read := withRetry(func(ctx context.Context, pin int) (float32, float32, int, error) {
+		cancel()
+		return failing(ctx, pin)
+	}, config.RetryPolicy{MaxAttempts: 5})
+	if _, _, _, err := read(ctx, 4); !IsTransient(err) {
+		t.Errorf("err = %v, want the last transient error", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want no retries once the read is abandoned", calls)
+	}
+}
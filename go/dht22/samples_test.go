@@ -0,0 +1,116 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestMedianRejectsSpike(t *testing.T) {
+	d := NewDHT22FromConfig(&config.Dht22Config{Pin: 4, Samples: 3})
+	d.sampleGap = 0
+	d.readFn = seriesReader([]reading{
+		{24.1, 55.0},
+		{61.0, 2.0},
+		{24.3, 55.4},
+	})
+
+	if err := d.read(); err != nil {
+		t.Fatal(err)
+	}
+	if d.Temp != float64(float32(24.3)) || d.Humidity != 55 {
+		t.Errorf("got %v/%v, want the median 24.3/55.0", d.Temp, d.Humidity)
+	}
+	if d.Stats.Reads != 1 {
+		t.Errorf("reads = %d, want the samples counted as one reading", d.Stats.Reads)
+	}
+}
+
+func TestMedianIgnoresFailedSamples(t *testing.T) {
+	calls := 0
+	d := NewDHT22(4, "canopy", "tent")
+	d.SetSamples(4)
+	d.sampleGap = 0
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		calls++
+		switch calls {
+		case 2:
+			return -1, -1, 3, errors.New("checksum mismatch")
+		case 3:
+			return 30, 40, 0, nil
+		}
+		return 20, 50, 0, nil
+	}
+
+	if err := d.read(); err != nil {
+		t.Fatalf("read with some good samples failed: %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("driver called %d times, want 4", calls)
+	}
+	if d.Temp != 20 || d.Humidity != 50 {
+		t.Errorf("got %v/%v, want the median of the good samples 20/50", d.Temp, d.Humidity)
+	}
+}
+
+func TestMedianAllSamplesFail(t *testing.T) {
+	d := NewDHT22(4, "canopy", "tent")
+	d.SetSamples(3)
+	d.sampleGap = 0
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		return -1, -1, 3, errors.New("checksum mismatch")
+	}
+	if err := d.read(); err == nil {
+		t.Fatal("expected an error when every sample fails")
+	}
+	if d.Stats.Failures != 1 {
+		t.Errorf("failures = %d, want 1", d.Stats.Failures)
+	}
+}
+
+func TestSingleSampleDefault(t *testing.T) {
+	calls := 0
+	d := NewDHT22FromConfig(&config.Dht22Config{Pin: 4})
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		calls++
+		return 24, 55, 0, nil
+	}
+	d.read()
+	if calls != 1 {
+		t.Errorf("driver called %d times, want 1 by default", calls)
+	}
+}
+
+func TestWarmUpDiscardsFirstReadings(t *testing.T) {
+	d := NewDHT22FromConfig(&config.Dht22Config{
+		Pin:    4,
+		WarmUp: 2,
+		Filter: &config.Dht22Filter{MaxTempDelta: 5},
+	})
+	d.readFn = seriesReader([]reading{
+		{-40, 0},
+		{80, 100},
+		{24, 55},
+		{24.5, 56},
+	})
+
+	for i := 0; i < 2; i++ {
+		d.read()
+		if d.Temp != 0 || d.Humidity != 0 {
+			t.Fatalf("warm-up reading %d was recorded as %v/%v", i, d.Temp, d.Humidity)
+		}
+	}
+	d.read()
+	if d.Temp != 24 || d.Humidity != 55 {
+		t.Errorf("got %v/%v, want the third reading 24/55 to be the first recorded", d.Temp, d.Humidity)
+	}
+	d.read()
+	if d.Temp != 24.5 || d.Rejected != 0 {
+		t.Errorf("warm-up readings leaked into the filter history: temp %v, rejected %d", d.Temp, d.Rejected)
+	}
+	if d.Stats.Reads != 4 {
+		t.Errorf("reads = %d, want warm-up reads counted", d.Stats.Reads)
+	}
+}
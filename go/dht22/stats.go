@@ -0,0 +1,33 @@
+package dht22
+
+import "time"
+
+// Stats counts read attempts against a sensor so a flaky bus can be spotted.
+// Skipped counts cycles where the sensor was not read at all because the
+// cycle deadline passed or its previous read had not finished.
+type Stats struct {
+	Reads            uint64        `json:"reads"`
+	Failures         uint64        `json:"failures"`
+	Skipped          uint64        `json:"skipped"`
+	LastReadDuration time.Duration `json:"last_read_duration"`
+	// LastRetries is how many retries the driver needed on the last read.
+	LastRetries int `json:"last_retries"`
+}
+
+// SuccessRate returns the fraction of reads that succeeded, or 0 before the
+// first read.
+func (s Stats) SuccessRate() float64 {
+	if s.Reads == 0 {
+		return 0
+	}
+	return float64(s.Reads-s.Failures) / float64(s.Reads)
+}
+
+func (s *Stats) record(duration time.Duration, retried int, err error) {
+	s.Reads++
+	s.LastReadDuration = duration
+	s.LastRetries = retried
+	if err != nil {
+		s.Failures++
+	}
+}
@@ -0,0 +1,42 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadStats(t *testing.T) {
+	calls := 0
+	d := NewDHT22(4, "canopy", "tent")
+	d.readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		calls++
+		time.Sleep(time.Millisecond)
+		if calls%4 == 0 {
+			return 0, 0, 3, errors.New("checksum mismatch")
+		}
+		return 24, 55, 0, nil
+	}
+
+	for i := 0; i < 8; i++ {
+		d.read()
+	}
+
+	stats := d.ReadStats()
+	if stats.Reads != 8 || stats.Failures != 2 {
+		t.Fatalf("got %d reads %d failures, want 8 reads 2 failures", stats.Reads, stats.Failures)
+	}
+	if rate := stats.SuccessRate(); rate != 0.75 {
+		t.Errorf("success rate = %v, want 0.75", rate)
+	}
+	if stats.LastReadDuration < time.Millisecond {
+		t.Errorf("last read duration = %v, want at least 1ms", stats.LastReadDuration)
+	}
+}
+
+func TestSuccessRateNoReads(t *testing.T) {
+	if rate := (Stats{}).SuccessRate(); rate != 0 {
+		t.Errorf("success rate = %v, want 0", rate)
+	}
+}
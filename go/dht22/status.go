@@ -0,0 +1,29 @@
+package dht22
+
+import "time"
+
+// Status describes the read cycle: whether it is running, when it last
+// completed and how many sensors it read successfully, and when the next
+// pass is due.
+type Status struct {
+	Running        bool          `json:"running"`
+	Interval       time.Duration `json:"interval"`
+	LastCycle      time.Time     `json:"last_cycle"`
+	LastCycleReads int           `json:"last_cycle_reads"`
+	NextCycle      time.Time     `json:"next_cycle"`
+}
+
+// UntilNext returns how long until the next pass, or zero if it is due or
+// the cycle is not running.
+func (s Status) UntilNext(now time.Time) time.Duration {
+	if !s.Running || s.NextCycle.IsZero() {
+		return 0
+	}
+	return max(s.NextCycle.Sub(now), 0)
+}
+
+func (dm *Manager) Status() Status {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.status
+}
@@ -0,0 +1,67 @@
+package dht22
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func TestStatus(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	m, sensors := newCycleManager(t, 0, 0, 0)
+	m.now = clock.Now
+	sensors[2].readFn = func(ctx context.Context, pin int) (float32, float32, int, error) {
+		return -1, -1, 3, errors.New("checksum mismatch")
+	}
+
+	if s := m.Status(); s.Running {
+		t.Fatal("status reports running before the cycle starts")
+	}
+
+	m.StartReadCycle(30 * time.Second)
+	deadline := time.Now().Add(time.Second)
+	for m.Status().NextCycle.IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatal("initial cycle did not complete")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	s := m.Status()
+	if !s.Running || s.Interval != 30*time.Second {
+		t.Errorf("status = %+v, want running every 30s", s)
+	}
+	if !s.LastCycle.Equal(clock.now) {
+		t.Errorf("last cycle = %v, want %v", s.LastCycle, clock.now)
+	}
+	if s.LastCycleReads != 2 {
+		t.Errorf("last cycle reads = %d, want 2", s.LastCycleReads)
+	}
+	if want := clock.now.Add(30 * time.Second); !s.NextCycle.Equal(want) {
+		t.Errorf("next cycle = %v, want %v", s.NextCycle, want)
+	}
+	if got := s.UntilNext(clock.now.Add(23 * time.Second)); got != 7*time.Second {
+		t.Errorf("until next = %v, want 7s", got)
+	}
+	if got := s.UntilNext(clock.now.Add(time.Minute)); got != 0 {
+		t.Errorf("until next = %v once overdue, want 0", got)
+	}
+
+	m.StopReadCycle()
+	if m.Status().Running {
+		t.Error("status reports running after stop")
+	}
+}
@@ -0,0 +1,45 @@
+package dht22
+
+import (
+	"slices"
+	"time"
+)
+
+// traceCycles is how many recent read cycles the manager keeps a trace of.
+const traceCycles = 10
+
+// CycleTrace records the timing of one pass over the sensors, for diagnosing
+// slow sensors and scheduling skew.
+type CycleTrace struct {
+	Start time.Time   `json:"start"`
+	End   time.Time   `json:"end"`
+	Reads []ReadTrace `json:"reads"`
+}
+
+// ReadTrace is one sensor's part of a cycle. Skipped reads were never
+// started, because the cycle deadline passed or the sensor was still busy.
+type ReadTrace struct {
+	Pin     int       `json:"pin"`
+	Name    string    `json:"name"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Retries int       `json:"retries"`
+	Skipped bool      `json:"skipped,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func (dm *Manager) recordTrace(t CycleTrace) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.trace = append(dm.trace, t)
+	if len(dm.trace) > traceCycles {
+		dm.trace = slices.Clone(dm.trace[len(dm.trace)-traceCycles:])
+	}
+}
+
+// Trace returns the recent read cycles, oldest first.
+func (dm *Manager) Trace() []CycleTrace {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return slices.Clone(dm.trace)
+}
@@ -0,0 +1,53 @@
+package dht22
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	m, sensors := newCycleManager(t, 0, 20*time.Millisecond, 0)
+	m.SetTimeouts(0, 10*time.Millisecond)
+	sensors[0].Name = "canopy"
+
+	m.readAll()
+	// Let the timed out read finish so the second cycle starts it again.
+	time.Sleep(20 * time.Millisecond)
+	m.readAll()
+
+	trace := m.Trace()
+	if len(trace) != 2 {
+		t.Fatalf("got %d cycles, want 2", len(trace))
+	}
+	for i, c := range trace {
+		if len(c.Reads) != 3 {
+			t.Fatalf("cycle %d has %d reads, want 3: %+v", i, len(c.Reads), c.Reads)
+		}
+		fast, slow, last := c.Reads[0], c.Reads[1], c.Reads[2]
+		if fast.Name != "canopy" || fast.Pin != 1 || fast.Error != "" || fast.Skipped {
+			t.Errorf("cycle %d: fast read = %+v", i, fast)
+		}
+		if fast.Start.Before(c.Start) || fast.End.Before(fast.Start) {
+			t.Errorf("cycle %d: fast read timing %v-%v outside cycle start %v", i, fast.Start, fast.End, c.Start)
+		}
+		if slow.Error == "" || slow.End.Sub(slow.Start) < 10*time.Millisecond {
+			t.Errorf("cycle %d: slow read = %+v, want a timeout after 10ms", i, slow)
+		}
+		if !last.Skipped {
+			t.Errorf("cycle %d: last read = %+v, want it skipped", i, last)
+		}
+		if c.End.Before(slow.End) {
+			t.Errorf("cycle %d ended at %v before its reads", i, c.End)
+		}
+	}
+}
+
+func TestTraceBounded(t *testing.T) {
+	m, _ := newCycleManager(t, 0)
+	for i := 0; i < traceCycles+3; i++ {
+		m.readAll()
+	}
+	if n := len(m.Trace()); n != traceCycles {
+		t.Errorf("kept %d cycles, want %d", n, traceCycles)
+	}
+}
@@ -3,11 +3,14 @@ module github.com/GreediGoblins/tentbox/go
 go 1.25.0
 
 require (
-	github.com/d2r2/go-dht v0.0.0-20200119175940-4ba96621a218 // indirect
+	github.com/d2r2/go-dht v0.0.0-20200119175940-4ba96621a218
+	github.com/morus12/dht22 v0.0.0-20170211005327-87805f7fca72
+)
+
+require (
 	github.com/d2r2/go-logger v0.0.0-20210606094344-60e9d1233e22 // indirect
 	github.com/d2r2/go-shell v0.0.0-20211022052110-f591c27e3e2e // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang/glog v1.2.5 // indirect
 	github.com/kidoman/embd v0.0.0-20170508013040-d3d8c0c5c68d // indirect
-	github.com/morus12/dht22 v0.0.0-20170211005327-87805f7fca72 // indirect
 )
@@ -1,17 +1,67 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/GreediGoblins/tentbox/go/config"
 )
 
+var errNoConfig = errors.New("no config provided; see --show-config-example")
+
+var subcommands = map[string]func(args []string) error{
+	"bench":    runBench,
+	"serve":    runServe,
+	"validate": runValidate,
+	"watch":    runWatch,
+}
+
+// subcommand returns the subcommand named by arg, or nil if arg is a flag
+// for the top-level command.
+func subcommand(arg string) (func(args []string) error, error) {
+	if strings.HasPrefix(arg, "-") {
+		return nil, nil
+	}
+	if run, ok := subcommands[arg]; ok {
+		return run, nil
+	}
+	return nil, fmt.Errorf("unknown command %q; valid commands: %s",
+		arg, strings.Join(slices.Sorted(maps.Keys(subcommands)), ", "))
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		run, err := subcommand(os.Args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if run != nil {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	showConfigExample := flag.Bool("show-config-example", false, "Show example config")
+	printSchema := flag.Bool("print-schema", false, "Print the JSON Schema for the config")
 	flag.Parse()
 
+	if !*showConfigExample && !*printSchema {
+		fmt.Fprintln(os.Stderr, errNoConfig)
+		os.Exit(1)
+	}
 	if *showConfigExample {
 		fmt.Println(config.ExampleConfig())
 	}
+	if *printSchema {
+		fmt.Println(string(config.ConfigSchema()))
+	}
 }
@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestSubcommand(t *testing.T) {
+	if run, err := subcommand("serve"); run == nil || err != nil {
+		t.Errorf("subcommand(serve) = %v, %v; want runServe", run != nil, err)
+	}
+	if run, err := subcommand("--show-config-example"); run != nil || err != nil {
+		t.Errorf("subcommand(--show-config-example) = %v, %v; want neither", run != nil, err)
+	}
+	_, err := subcommand("sevre")
+	want := `unknown command "sevre"; valid commands: bench, serve, validate, watch`
+	if err == nil || err.Error() != want {
+		t.Errorf("subcommand(sevre) err = %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// runServe implements `tentbox serve`: it runs the sensor read cycle for the
+// given config until interrupted, reloading the config on SIGHUP.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to the config file, or - to read it from stdin")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		return errNoConfig
+	}
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	return serve(ctx, cfg, reloadOnSignal(ctx, *configPath, hup))
+}
+
+// reloadOnSignal loads the config at path again each time hup fires and
+// sends the result. A config that fails to load is reported and skipped, so
+// the running one stays in place.
+func reloadOnSignal(ctx context.Context, path string, hup <-chan os.Signal) <-chan *config.Config {
+	reloads := make(chan *config.Config)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+			}
+			if path == config.StdinPath {
+				fmt.Println("Cannot reload a config read from stdin")
+				continue
+			}
+			cfg, err := config.LoadConfig(path)
+			if err != nil {
+				fmt.Printf("Failed to reload config: %v\n", err)
+				continue
+			}
+			select {
+			case reloads <- cfg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return reloads
+}
+
+// serve runs until ctx is done, applying each config received on reloads to
+// the running read cycle. A config without any sensors is valid and simply
+// leaves the read cycle idle. Bus sensors are read on the DHT22 read interval
+// and are only opened at startup.
+func serve(ctx context.Context, cfg *config.Config, reloads <-chan *config.Config) error {
+	printWarnings(cfg)
+	manager, interval, err := newDHT22Manager(cfg)
+	if err != nil {
+		return err
+	}
+	if len(manager.Readings()) == 0 {
+		fmt.Println("No DHT22 sensors configured")
+	}
+	bus, err := openBusSensors(cfg)
+	if err != nil {
+		return err
+	}
+	defer bus.Close()
+
+	manager.StartReadCycle(interval)
+	defer manager.StopReadCycle()
+	bus.run(ctx, interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case next := <-reloads:
+			printWarnings(next)
+			interval, err := applyDHT22Config(manager, next)
+			if err != nil {
+				fmt.Printf("Failed to apply reloaded config: %v\n", err)
+				continue
+			}
+			manager.SetInterval(interval)
+			if !reflect.DeepEqual(cfg.SHT31, next.SHT31) || !reflect.DeepEqual(cfg.Analog, next.Analog) {
+				fmt.Println("SHT31 and analog changes take effect after a restart")
+			}
+			cfg = next
+			fmt.Println("Reloaded config")
+		}
+	}
+}
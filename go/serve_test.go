@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestServeEmptyConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := config.SaveConfig(path, &config.Config{}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("empty config failed to load: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := serve(ctx, cfg, nil); err != nil {
+		t.Errorf("serve with an empty config returned %v", err)
+	}
+}
+
+func TestServeRequiresConfig(t *testing.T) {
+	if err := runServe(nil); !errors.Is(err, errNoConfig) {
+		t.Errorf("runServe without --config = %v, want %v", err, errNoConfig)
+	}
+}
+
+func TestServeAppliesReloads(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reloads := make(chan *config.Config)
+	done := make(chan error, 1)
+	go func() { done <- serve(ctx, &config.Config{}, reloads) }()
+
+	reloads <- &config.Config{ReadCycle: &config.ReadCycle{Interval: config.Duration(time.Minute)}}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve returned %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serve did not stop after a reload")
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := config.SaveConfig(path, &config.Config{
+		Dht22: []*config.Dht22Config{{Pin: 4, Name: "canopy"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hup := make(chan os.Signal)
+	reloads := reloadOnSignal(ctx, path, hup)
+
+	hup <- syscall.SIGHUP
+	select {
+	case cfg := <-reloads:
+		if len(cfg.Dht22) != 1 || cfg.Dht22[0].Name != "canopy" {
+			t.Errorf("reloaded config = %+v", cfg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"dht22": [{"pin": 4}, {"pin": 4}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hup <- syscall.SIGHUP
+	select {
+	case cfg := <-reloads:
+		t.Errorf("invalid config was sent: %+v", cfg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestApplyDHT22ConfigReload(t *testing.T) {
+	manager, interval, err := newDHT22Manager(&config.Config{
+		Dht22: []*config.Dht22Config{{Pin: 4, Name: "canopy"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interval != defaultReadInterval {
+		t.Errorf("interval = %v, want the default %v", interval, defaultReadInterval)
+	}
+
+	interval, err = applyDHT22Config(manager, &config.Config{
+		ReadCycle: &config.ReadCycle{Interval: config.Duration(10 * time.Second)},
+		Dht22:     []*config.Dht22Config{{Pin: 4, Name: "top of canopy"}, {Pin: 5, Name: "floor"}},
+	})
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if interval != 10*time.Second {
+		t.Errorf("interval = %v, want 10s from the reloaded config", interval)
+	}
+	readings := manager.Readings()
+	if len(readings) != 2 || readings[0].Name != "top of canopy" || readings[1].Name != "floor" {
+		t.Errorf("readings after reload = %+v", readings)
+	}
+}
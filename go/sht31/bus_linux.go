@@ -0,0 +1,74 @@
+package sht31
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// i2cSlave is the i2c-dev ioctl that selects the target device address.
+const i2cSlave = 0x0703
+
+// LinuxBus talks to an I2C bus through /dev/i2c-N.
+type LinuxBus struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenBus opens I2C bus n, which is 1 on most Raspberry Pis.
+func OpenBus(n int) (*LinuxBus, error) {
+	f, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", n), os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open i2c bus %d: %w", n, err)
+	}
+	return &LinuxBus{f: f}, nil
+}
+
+func (b *LinuxBus) Tx(addr uint16, w, r []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, b.f.Fd(), i2cSlave, uintptr(addr)); errno != 0 {
+		return fmt.Errorf("failed to select i2c address 0x%02x: %w", addr, errno)
+	}
+	if len(w) > 0 {
+		if _, err := b.f.Write(w); err != nil {
+			return err
+		}
+	}
+	if len(r) > 0 {
+		if _, err := b.f.Read(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *LinuxBus) Close() error {
+	return b.f.Close()
+}
+
+// OpenConfig opens every bus named in cs and builds the sensors on them.
+func OpenConfig(cs []*config.SHT31) ([]*LinuxBus, []*SHT31, error) {
+	buses := make(map[int]*LinuxBus)
+	var opened []*LinuxBus
+	sensors := make([]*SHT31, 0, len(cs))
+	for _, c := range cs {
+		bus, ok := buses[c.BusNumber()]
+		if !ok {
+			var err error
+			if bus, err = OpenBus(c.BusNumber()); err != nil {
+				for _, b := range opened {
+					b.Close()
+				}
+				return nil, nil, fmt.Errorf("sht31 %q: %w", c.Name, err)
+			}
+			buses[c.BusNumber()] = bus
+			opened = append(opened, bus)
+		}
+		sensors = append(sensors, NewSHT31FromConfig(bus, c))
+	}
+	return opened, sensors, nil
+}
@@ -0,0 +1,115 @@
+package sht31
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// DefaultAddress is the SHT31's I2C address with the ADDR pin pulled low.
+// Pulling it high moves the sensor to 0x45.
+const DefaultAddress = 0x44
+
+// measureCmd starts a single-shot, high-repeatability measurement with clock
+// stretching disabled.
+var measureCmd = []byte{0x24, 0x00}
+
+// measureTime is the worst-case conversion time for a high-repeatability
+// measurement.
+const measureTime = 16 * time.Millisecond
+
+var errCRC = errors.New("crc mismatch")
+
+// Bus is an I2C bus that the sensor can be reached through.
+type Bus interface {
+	// Tx writes w to the device at addr and then reads len(r) bytes into r.
+	// Either may be empty.
+	Tx(addr uint16, w, r []byte) error
+}
+
+type SHT31 struct {
+	sync.RWMutex
+	bus         Bus
+	addr        uint16
+	measureTime time.Duration
+	Name        string  `json:"name"`
+	Location    string  `json:"location"`
+	Temp        float64 `json:"temp"`
+	Humidity    float64 `json:"humidity"`
+}
+
+func NewSHT31(bus Bus, addr uint16, name string, location string) *SHT31 {
+	return &SHT31{
+		bus:         bus,
+		addr:        addr,
+		measureTime: measureTime,
+		Name:        name,
+		Location:    location,
+	}
+}
+
+// NewSHT31FromConfig builds the sensor for c on bus, using DefaultAddress
+// when c has no address.
+func NewSHT31FromConfig(bus Bus, c *config.SHT31) *SHT31 {
+	addr := uint16(DefaultAddress)
+	if c.Address != 0 {
+		addr = uint16(c.Address)
+	}
+	return NewSHT31(bus, addr, c.Name, c.Location)
+}
+
+// Read triggers a measurement and stores the result.
+func (s *SHT31) Read() error {
+	if err := s.bus.Tx(s.addr, measureCmd, nil); err != nil {
+		return fmt.Errorf("sht31 0x%02x: failed to start measurement: %w", s.addr, err)
+	}
+	time.Sleep(s.measureTime)
+	data := make([]byte, 6)
+	if err := s.bus.Tx(s.addr, nil, data); err != nil {
+		return fmt.Errorf("sht31 0x%02x: failed to read measurement: %w", s.addr, err)
+	}
+	temp, humidity, err := decode(data)
+	if err != nil {
+		return fmt.Errorf("sht31 0x%02x: %w", s.addr, err)
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.Temp = temp
+	s.Humidity = humidity
+	return nil
+}
+
+// decode converts a measurement (temperature word, crc, humidity word, crc)
+// into degrees Celsius and percent relative humidity.
+func decode(data []byte) (float64, float64, error) {
+	if len(data) != 6 {
+		return 0, 0, fmt.Errorf("expected 6 bytes, got %d", len(data))
+	}
+	if crc8(data[0:2]) != data[2] || crc8(data[3:5]) != data[5] {
+		return 0, 0, errCRC
+	}
+	rawTemp := uint16(data[0])<<8 | uint16(data[1])
+	rawHumidity := uint16(data[3])<<8 | uint16(data[4])
+	temp := -45 + 175*float64(rawTemp)/65535
+	humidity := 100 * float64(rawHumidity) / 65535
+	return temp, humidity, nil
+}
+
+// crc8 is the SHT3x checksum: polynomial 0x31, initial value 0xFF.
+func crc8(data []byte) byte {
+	crc := byte(0xFF)
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x31
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
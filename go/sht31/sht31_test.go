@@ -0,0 +1,107 @@
+package sht31
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// mockBus answers a measurement command with a canned response.
+type mockBus struct {
+	addr     uint16
+	response []byte
+	written  [][]byte
+}
+
+func (m *mockBus) Tx(addr uint16, w, r []byte) error {
+	if addr != m.addr {
+		return errors.New("no device at address")
+	}
+	if len(w) > 0 {
+		m.written = append(m.written, append([]byte(nil), w...))
+	}
+	copy(r, m.response)
+	return nil
+}
+
+func measurement(rawTemp, rawHumidity uint16) []byte {
+	t := []byte{byte(rawTemp >> 8), byte(rawTemp)}
+	h := []byte{byte(rawHumidity >> 8), byte(rawHumidity)}
+	return []byte{t[0], t[1], crc8(t), h[0], h[1], crc8(h)}
+}
+
+func TestCRC8(t *testing.T) {
+	// Example from the SHT3x datasheet.
+	if got := crc8([]byte{0xBE, 0xEF}); got != 0x92 {
+		t.Errorf("crc8(0xBEEF) = 0x%02x, want 0x92", got)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name                   string
+		rawTemp, rawHumidity   uint16
+		wantTemp, wantHumidity float64
+	}{
+		{"minimum", 0x0000, 0x0000, -45, 0},
+		{"maximum", 0xFFFF, 0xFFFF, 130, 100},
+		{"room", 0x6666, 0x8000, 25, 50.0008},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp, humidity, err := decode(measurement(tt.rawTemp, tt.rawHumidity))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(temp-tt.wantTemp) > 0.001 || math.Abs(humidity-tt.wantHumidity) > 0.001 {
+				t.Errorf("got %v/%v, want %v/%v", temp, humidity, tt.wantTemp, tt.wantHumidity)
+			}
+		})
+	}
+}
+
+func TestDecodeBadCRC(t *testing.T) {
+	data := measurement(0x6666, 0x8000)
+	data[5] ^= 0xFF
+	if _, _, err := decode(data); !errors.Is(err, errCRC) {
+		t.Errorf("decode() = %v, want a crc error", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	bus := &mockBus{addr: DefaultAddress, response: measurement(0x6666, 0x8000)}
+	s := NewSHT31(bus, DefaultAddress, "canopy", "tent")
+	s.measureTime = 0
+
+	if err := s.Read(); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(bus.written) != 1 || !bytes.Equal(bus.written[0], measureCmd) {
+		t.Errorf("wrote %x, want the measurement command", bus.written)
+	}
+	if math.Abs(s.Temp-25) > 0.001 || math.Abs(s.Humidity-50) > 0.01 {
+		t.Errorf("got %v/%v, want 25/50", s.Temp, s.Humidity)
+	}
+}
+
+func TestReadWrongAddress(t *testing.T) {
+	bus := &mockBus{addr: 0x45}
+	s := NewSHT31(bus, DefaultAddress, "canopy", "tent")
+	s.measureTime = 0
+	if err := s.Read(); err == nil {
+		t.Fatal("expected an error reading an absent device")
+	}
+}
+
+func TestNewSHT31FromConfig(t *testing.T) {
+	s := NewSHT31FromConfig(nil, &config.SHT31{Name: "canopy", Location: "tent"})
+	if s.addr != DefaultAddress || s.Name != "canopy" || s.Location != "tent" {
+		t.Errorf("got addr 0x%02x %q/%q, want the default address and canopy/tent", s.addr, s.Name, s.Location)
+	}
+	if s := NewSHT31FromConfig(nil, &config.SHT31{Address: 0x45}); s.addr != 0x45 {
+		t.Errorf("got addr 0x%02x, want 0x45", s.addr)
+	}
+}
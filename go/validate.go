@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+// runValidate implements `tentbox validate`: it loads the config, reporting
+// any errors, prints its lint warnings and with --strict fails on them too.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to the config file, or - to read it from stdin")
+	strict := fs.Bool("strict", false, "Treat warnings as errors")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		return errNoConfig
+	}
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	name := configName(*configPath)
+	if warnings := printWarnings(cfg); *strict && len(warnings) > 0 {
+		return fmt.Errorf("%s has %d warnings", name, len(warnings))
+	}
+	fmt.Printf("%s is valid\n", name)
+	return nil
+}
+
+// configName describes the config at path for messages, in the same words
+// LoadConfig uses in its errors.
+func configName(path string) string {
+	if path == config.StdinPath {
+		return "config from stdin"
+	}
+	return path
+}
+
+// printWarnings prints the config's lint warnings and returns them.
+func printWarnings(cfg *config.Config) []config.Warning {
+	warnings := cfg.Lint()
+	for _, w := range warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	return warnings
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+)
+
+func TestRunValidate(t *testing.T) {
+	dir := t.TempDir()
+	clean := filepath.Join(dir, "clean.json")
+	risky := filepath.Join(dir, "risky.json")
+	if err := config.SaveConfig(clean, &config.Config{
+		Dht22: []*config.Dht22Config{{Pin: 4, Name: "canopy"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveConfig(risky, &config.Config{
+		Dht22: []*config.Dht22Config{{Pin: 1, Name: "canopy"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	example := filepath.Join(dir, "example.json")
+	if err := os.WriteFile(example, []byte(config.ExampleConfig()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"--config", clean}, false},
+		{[]string{"--config", clean, "--strict"}, false},
+		{[]string{"--config", risky}, false},
+		{[]string{"--config", risky, "--strict"}, true},
+		{[]string{"--config", example, "--strict"}, false},
+	} {
+		if err := runValidate(tc.args); (err != nil) != tc.wantErr {
+			t.Errorf("runValidate(%v) = %v, want error %v", tc.args, err, tc.wantErr)
+		}
+	}
+}
+
+func TestConfigName(t *testing.T) {
+	if got := configName(config.StdinPath); got != "config from stdin" {
+		t.Errorf("configName(%q) = %q, want config from stdin", config.StdinPath, got)
+	}
+	if got := configName("tentbox.json"); got != "tentbox.json" {
+		t.Errorf("configName(tentbox.json) = %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/config"
+	"github.com/GreediGoblins/tentbox/go/dht22"
+)
+
+const (
+	clearScreen         = "\033[H\033[2J"
+	defaultReadInterval = 30 * time.Second
+)
+
+// runWatch implements `tentbox watch`: it reads the configured sensors and
+// redraws a table of their current values until interrupted.
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to the config file, or - to read it from stdin")
+	interval := fs.Duration("interval", time.Second, "How often to redraw the table")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		return errNoConfig
+	}
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	manager, readInterval, err := newDHT22Manager(cfg)
+	if err != nil {
+		return err
+	}
+	bus, err := openBusSensors(cfg)
+	if err != nil {
+		return err
+	}
+	defer bus.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	manager.StartReadCycle(readInterval)
+	defer manager.StopReadCycle()
+	bus.run(ctx, readInterval)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		fmt.Print(clearScreen + renderReadings(manager.Readings(), time.Now()) + renderBusReadings(bus.readings()))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// newDHT22Manager builds a manager for every DHT22 in cfg and returns the
+// read interval to run it at.
+func newDHT22Manager(cfg *config.Config) (*dht22.Manager, time.Duration, error) {
+	manager := dht22.NewManager()
+	interval, err := applyDHT22Config(manager, cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+	return manager, interval, nil
+}
+
+// applyDHT22Config brings manager's sensors and read cycle settings in line
+// with cfg and returns the read interval. It is used at startup and again
+// for every config reload.
+func applyDHT22Config(manager *dht22.Manager, cfg *config.Config) (time.Duration, error) {
+	var sensors []*config.Dht22Config
+	for _, z := range cfg.EffectiveZones() {
+		sensors = append(sensors, z.Dht22...)
+	}
+	rc := cfg.ReadCycle
+	if rc == nil {
+		rc = &config.ReadCycle{}
+	}
+	if err := manager.SetBackend(cfg.DhtBackend); err != nil {
+		return 0, err
+	}
+	manager.SetRetryPolicy(rc.Retry)
+	if err := manager.Reconcile(sensors); err != nil {
+		return 0, err
+	}
+
+	manager.SetTimeouts(time.Duration(rc.ReadTimeout), time.Duration(rc.CycleDeadline))
+	manager.SetInitialRead(!rc.SkipInitialRead)
+	manager.SetTimestampSource(rc.TimestampSource)
+	if rc.Health != nil {
+		manager.SetHealthCheck(rc.Health.Window, rc.Health.MinSuccessRate)
+	} else {
+		manager.SetHealthCheck(0, 0)
+	}
+	interval := defaultReadInterval
+	if rc.Interval > 0 {
+		interval = time.Duration(rc.Interval)
+	}
+	return interval, nil
+}
+
+func renderReadings(readings []dht22.Reading, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "tentbox watch - %s\n\n", now.Format("2006-01-02 15:04:05"))
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PIN\tNAME\tLOCATION\tTEMP\tHUMIDITY\tSTATUS")
+	for _, r := range readings {
+		status := "ok"
+		switch {
+		case !r.Enabled:
+			status = "disabled"
+		case r.Quality == dht22.QualityGap || r.Quality == dht22.QualityStale:
+			status = string(r.Quality)
+			if r.LastError != nil {
+				status += ": " + r.LastError.Message
+			}
+		case r.LastError != nil:
+			status = "error: " + r.LastError.Message
+		case r.Quality == dht22.QualityRejected:
+			status = "rejected"
+		}
+		temp, humidity := "-", "-"
+		if r.Quality != dht22.QualityGap {
+			temp = fmt.Sprintf("%.*f°C", r.Decimals, r.Temp)
+			humidity = fmt.Sprintf("%.*f%%", r.Decimals, r.Humidity)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", r.Pin, r.Name, r.Location, temp, humidity, status)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GreediGoblins/tentbox/go/dht22"
+)
+
+func TestRenderReadings(t *testing.T) {
+	readings := []dht22.Reading{
+		{Pin: 4, Name: "canopy", Location: "tent", Temp: 24.26, Humidity: 55.04, Enabled: true, Decimals: 1},
+		{Pin: 17, Name: "floor", Location: "tent", Temp: 21, Humidity: 61.5, Enabled: false, Decimals: 1},
+		{Pin: 22, Name: "ambient", Location: "room", Enabled: true, LastError: &dht22.LastError{Message: "read timed out"}, Decimals: 1},
+	}
+	now := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	want := "tentbox watch - 2024-03-01 14:30:00\n" +
+		"\n" +
+		"PIN  NAME     LOCATION  TEMP    HUMIDITY  STATUS\n" +
+		"4    canopy   tent      24.3°C  55.0%     ok\n" +
+		"17   floor    tent      21.0°C  61.5%     disabled\n" +
+		"22   ambient  room      0.0°C   0.0%      error: read timed out\n"
+	if got := renderReadings(readings, now); got != want {
+		t.Errorf("renderReadings() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderReadingsGapsAndDecimals(t *testing.T) {
+	timeout := &dht22.LastError{Message: "read timed out"}
+	readings := []dht22.Reading{
+		{Pin: 4, Name: "canopy", Temp: 24.26, Humidity: 55.04, Enabled: true, Decimals: 2},
+		{Pin: 17, Name: "floor", Temp: 21, Humidity: 61.5, Enabled: true, Decimals: 0,
+			Quality: dht22.QualityStale, LastError: timeout},
+		{Pin: 22, Name: "ambient", Temp: 19, Humidity: 40, Enabled: true, Decimals: 1,
+			Quality: dht22.QualityGap, LastError: timeout},
+	}
+	now := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	want := "tentbox watch - 2024-03-01 14:30:00\n" +
+		"\n" +
+		"PIN  NAME     LOCATION  TEMP     HUMIDITY  STATUS\n" +
+		"4    canopy             24.26°C  55.04%    ok\n" +
+		"17   floor              21°C     62%       stale: read timed out\n" +
+		"22   ambient            -        -         gap: read timed out\n"
+	if got := renderReadings(readings, now); got != want {
+		t.Errorf("renderReadings() =\n%s\nwant\n%s", got, want)
+	}
+}